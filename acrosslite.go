@@ -0,0 +1,459 @@
+package puz
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const acrossLiteHeader string = "<ACROSS PUZZLE>"
+const acrossLiteHeaderV2 string = "<ACROSS PUZZLE V2>"
+const acrossLiteMarkCircles string = "MARK;"
+const acrossLiteEmptyClue string = "-" // Written for empty clues because blank lines are skipped when decoding
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// TextParseError reports the line in an Across Lite text file where parsing failed
+type TextParseError struct {
+	Line int   // The line number, starting at 1
+	Err  error // The reason parsing failed
+}
+
+func (e *TextParseError) Error() string {
+	return fmt.Sprintf("Line %d: %v", e.Line, e.Err)
+}
+
+func (e *TextParseError) Unwrap() error {
+	return e.Err
+}
+
+// acrossLiteSection is a tagged section in an Across Lite text file, e.g. <GRID>
+type acrossLiteSection struct {
+	line  int        // The line of the section tag
+	lines []textLine // The non empty lines in the section
+}
+
+type textLine struct {
+	num  int    // The line number, starting at 1
+	text string // The line with surrounding whitespace removed
+}
+
+// DecodeAcrossLiteText parses a puzzle in the Across Lite text format (v1 or v2) and returns Puzzle.
+//
+// The <TITLE>, <AUTHOR>, <COPYRIGHT>, <SIZE>, <GRID>, <REBUS>, <ACROSS>, <DOWN>, and <NOTEPAD> sections are supported.
+// In v2 files a MARK; line in the <REBUS> section means lowercase letters in the grid are circled.
+// Clues are numbered with Board.GetWords, a clue of only "-" is the placeholder for an empty clue and is read as empty.
+// Errors are returned as a TextParseError with the line that caused them.
+func DecodeAcrossLiteText(data []byte) (*Puzzle, error) {
+	isUTF8 := bytes.HasPrefix(data, utf8BOM)
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+
+	first := 0
+	for first < len(lines) && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+
+	if first == len(lines) {
+		return nil, &TextParseError{1, MissingTextHeaderError}
+	}
+
+	header := strings.TrimSpace(lines[first])
+	if header != acrossLiteHeader && header != acrossLiteHeaderV2 {
+		return nil, &TextParseError{first + 1, MissingTextHeaderError}
+	}
+	isV2 := header == acrossLiteHeaderV2
+
+	sections := map[string]*acrossLiteSection{}
+	var current *acrossLiteSection
+
+	for i := first + 1; i < len(lines); i++ {
+		text := strings.TrimSpace(lines[i])
+
+		if strings.HasPrefix(text, "<") && strings.HasSuffix(text, ">") {
+			name := text[1 : len(text)-1]
+
+			switch name {
+			case "TITLE", "AUTHOR", "COPYRIGHT", "SIZE", "GRID", "REBUS", "ACROSS", "DOWN", "NOTEPAD":
+			default:
+				return nil, &TextParseError{i + 1, UnknownTextSectionError}
+			}
+
+			if _, ok := sections[name]; ok {
+				return nil, &TextParseError{i + 1, DuplicateTextSectionError}
+			}
+
+			if name == "REBUS" && !isV2 {
+				return nil, &TextParseError{i + 1, TextSectionRequiresV2Error}
+			}
+
+			current = &acrossLiteSection{i + 1, nil}
+			sections[name] = current
+			continue
+		}
+
+		if current == nil {
+			if text == "" {
+				continue
+			}
+
+			return nil, &TextParseError{i + 1, UnexpectedTextLineError}
+		}
+
+		// blank lines are only kept in the notepad
+		if text == "" && current != sections["NOTEPAD"] {
+			continue
+		}
+
+		current.lines = append(current.lines, textLine{i + 1, text})
+	}
+
+	for _, name := range []string{"TITLE", "AUTHOR", "COPYRIGHT", "SIZE", "GRID", "ACROSS", "DOWN"} {
+		if _, ok := sections[name]; !ok {
+			return nil, &TextParseError{len(lines), fmt.Errorf("%w: <%s>", MissingTextSectionError, name)}
+		}
+	}
+
+	grid := sections["GRID"]
+	if len(grid.lines) == 0 {
+		return nil, &TextParseError{grid.line, EmptyTextGridError}
+	}
+
+	width, height, err := parseAcrossLiteSize(sections["SIZE"])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(grid.lines) != height {
+		return nil, &TextParseError{grid.line, TextGridSizeMismatchError}
+	}
+
+	for _, line := range grid.lines {
+		if len(line.text) != width {
+			return nil, &TextParseError{line.num, TextGridSizeMismatchError}
+		}
+	}
+
+	markCircles, rebuses, err := parseAcrossLiteRebus(sections["REBUS"])
+	if err != nil {
+		return nil, err
+	}
+
+	puzzle := NewPuzzle(uint8(width), uint8(height))
+
+	text := func(value string) string {
+		if !isUTF8 {
+			return value
+		}
+
+		converted, _ := puzzle.textFromUTF8(value)
+		return converted
+	}
+
+	if isUTF8 {
+		var texts []string
+		for _, section := range sections {
+			for _, line := range section.lines {
+				texts = append(texts, line.text)
+			}
+		}
+		puzzle.setTextVersion(texts)
+	}
+
+	for y, line := range grid.lines {
+		for x := range width {
+			ch := line.text[x]
+
+			if ch == SolidSquare {
+				puzzle.Board[y][x].Answer = SolidSquare
+				puzzle.Board[y][x].Guess = SolidSquare
+				continue
+			}
+
+			if rebus, ok := rebuses[ch]; ok {
+				err = puzzle.SetRebus(x, y, text(rebus.value))
+				if err != nil {
+					return nil, &TextParseError{line.num, err}
+				}
+				puzzle.Board[y][x].Answer = rebus.short
+				continue
+			}
+
+			if ch >= 'a' && ch <= 'z' {
+				if !markCircles {
+					return nil, &TextParseError{line.num, LowercaseTextGridError}
+				}
+
+				puzzle.Board[y][x].SetCircled(true)
+				puzzle.AddExtraSection(MarkupBoardSection)
+				ch -= 'a' - 'A'
+			}
+
+			puzzle.Board[y][x].Answer = ch
+		}
+	}
+
+	puzzle.Title = text(sectionText(sections["TITLE"], " "))
+	puzzle.Author = text(sectionText(sections["AUTHOR"], " "))
+	puzzle.Copyright = text(sectionText(sections["COPYRIGHT"], " "))
+	if notepad, ok := sections["NOTEPAD"]; ok {
+		puzzle.Notes = text(strings.TrimSpace(sectionText(notepad, "\n")))
+	}
+
+	words := puzzle.Board.GetWords()
+	var clues Clues
+	next := map[Direction]int{}
+
+	for _, dir := range []Direction{Across, Down} {
+		name := strings.ToUpper(directionName(dir))
+		section := sections[name]
+
+		expected := 0
+		for _, word := range words {
+			if word.Direction == dir {
+				expected++
+			}
+		}
+
+		if len(section.lines) != expected {
+			return nil, &TextParseError{section.line, &ClueCountMismatchError{expected, len(section.lines)}}
+		}
+	}
+
+	for _, word := range words {
+		line := sections[strings.ToUpper(directionName(word.Direction))].lines[next[word.Direction]]
+		next[word.Direction]++
+
+		clue := text(line.text)
+		if strings.TrimSpace(clue) == acrossLiteEmptyClue {
+			clue = ""
+		}
+
+		clues = append(clues, NewClue(clue, word.Num, word.StartX, word.StartY, word.Direction))
+	}
+
+	puzzle.SetClues(clues)
+
+	return puzzle, nil
+}
+
+// EncodeAcrossLiteText encodes the puzzle in the Across Lite text format.
+//
+// The v2 format is used when the puzzle has rebus or circled cells, otherwise v1 is used.
+// Data the text format can not hold, such as player guesses and the timer, is reported in the returned warnings.
+// Returns PuzzleIsScrambledError if the puzzle is scrambled because the answers can not be exported.
+func EncodeAcrossLiteText(puzzle *Puzzle) ([]byte, []ConversionWarning, error) {
+	var warnings conversionWarnings
+
+	if puzzle.Scrambled() {
+		return nil, nil, PuzzleIsScrambledError
+	}
+
+	width := puzzle.Board.Width()
+	height := puzzle.Board.Height()
+
+	hasCircles := false
+	hasRebus := false
+
+	for y := range height {
+		for x := range width {
+			cell := puzzle.Board[y][x]
+
+			if cell.RebusKey != 0 {
+				hasRebus = true
+			}
+
+			if cell.IsCircled() {
+				hasCircles = true
+			}
+
+			if cell.Guess != EmptyStateSquare && !puzzle.Board.IsSolidSquare(x, y) {
+				warnings.add("guesses", "player guesses are not supported by the text format and were dropped")
+			}
+
+			if cell.Markup&^SquareCircled != 0 {
+				warnings.add("markup", "incorrect and given markup is not supported by the text format and was dropped")
+			}
+		}
+	}
+
+	if puzzle.HasExtraSection(TimerSection) {
+		warnings.add("timer", "the timer is not supported by the text format and was dropped")
+	}
+
+	if puzzle.PuzzleType == Diagramless {
+		warnings.add("diagramless", "the diagramless puzzle type is not supported by the text format and was dropped")
+	}
+
+	isV2 := hasCircles || hasRebus
+
+	symbols := newRebusSymbolTable(puzzle.Board)
+	var rebusLines []string
+
+	var grid strings.Builder
+	for y := range height {
+		grid.WriteString("\t")
+
+		for x := range width {
+			cell := puzzle.Board[y][x]
+			ch := cell.Answer
+
+			if cell.RebusKey != 0 {
+				entry, ok := rebusEntryByKey(puzzle.Extras.RebusTable, int(cell.RebusKey))
+				if !ok {
+					warnings.add("rebus", fmt.Sprintf("rebus at %d,%d is missing from the rebus table and was dropped", x, y))
+				} else {
+					symbol, isNew, err := symbols.symbol(entry.Value)
+					if err != nil {
+						return nil, nil, err
+					}
+
+					if isNew {
+						rebusLines = append(rebusLines, fmt.Sprintf("%c:%s:%c", symbol, entry.Value, cell.Answer))
+					}
+
+					ch = symbol
+
+					if cell.IsCircled() {
+						warnings.add("circles", "circled rebus cells can not be marked in the text format and were not circled")
+					}
+				}
+			} else if cell.IsCircled() && isLetter(ch) {
+				ch = strings.ToLower(string(ch))[0]
+			} else if ch == EmptySolutionSquare {
+				warnings.add("solution", "cells without an answer are not supported by the text format and were written as X")
+				ch = 'X'
+			}
+
+			grid.WriteByte(ch)
+		}
+
+		grid.WriteString("\n")
+	}
+
+	var out strings.Builder
+
+	if isV2 {
+		out.WriteString(acrossLiteHeaderV2 + "\n")
+	} else {
+		out.WriteString(acrossLiteHeader + "\n")
+	}
+
+	writeSection := func(name string, lines ...string) {
+		fmt.Fprintf(&out, "<%s>\n", name)
+		for _, line := range lines {
+			fmt.Fprintf(&out, "\t%s\n", line)
+		}
+	}
+
+	writeSection("TITLE", puzzle.Title)
+	writeSection("AUTHOR", puzzle.Author)
+	writeSection("COPYRIGHT", puzzle.Copyright)
+	writeSection("SIZE", fmt.Sprintf("%dx%d", width, height))
+	out.WriteString("<GRID>\n")
+	out.WriteString(grid.String())
+
+	if hasCircles || len(rebusLines) > 0 {
+		if hasCircles {
+			rebusLines = append([]string{acrossLiteMarkCircles}, rebusLines...)
+		}
+		writeSection("REBUS", rebusLines...)
+	}
+
+	for _, dir := range []Direction{Across, Down} {
+		var lines []string
+		for _, clue := range puzzle.GetCluesByDirection(dir) {
+			line := strings.ReplaceAll(clue.Clue, "\n", " ")
+
+			switch strings.TrimSpace(line) {
+			case "":
+				line = acrossLiteEmptyClue
+				warnings.add("clues", fmt.Sprintf("%d %s has an empty clue that was written as %s", clue.Num, directionName(dir), acrossLiteEmptyClue))
+			case acrossLiteEmptyClue:
+				warnings.add("clues", fmt.Sprintf("%d %s has the clue %s that is read back as an empty clue", clue.Num, directionName(dir), acrossLiteEmptyClue))
+			}
+
+			lines = append(lines, line)
+		}
+		writeSection(strings.ToUpper(directionName(dir)), lines...)
+	}
+
+	if puzzle.Notes != "" {
+		writeSection("NOTEPAD", strings.Split(puzzle.Notes, "\n")...)
+	}
+
+	return []byte(out.String()), warnings, nil
+}
+
+// sectionText joins the lines of a section with sep.
+func sectionText(section *acrossLiteSection, sep string) string {
+	var parts []string
+	for _, line := range section.lines {
+		parts = append(parts, line.text)
+	}
+
+	return strings.Join(parts, sep)
+}
+
+// parseAcrossLiteSize parses a <SIZE> section in the form WIDTHxHEIGHT.
+func parseAcrossLiteSize(section *acrossLiteSection) (int, int, error) {
+	if len(section.lines) != 1 {
+		return 0, 0, &TextParseError{section.line, InvalidTextSizeError}
+	}
+
+	line := section.lines[0]
+	widthStr, heightStr, ok := strings.Cut(strings.ToLower(line.text), "x")
+	if !ok {
+		return 0, 0, &TextParseError{line.num, InvalidTextSizeError}
+	}
+
+	width, err := strconv.Atoi(strings.TrimSpace(widthStr))
+	if err != nil || width < 1 || width > 255 {
+		return 0, 0, &TextParseError{line.num, InvalidTextSizeError}
+	}
+
+	height, err := strconv.Atoi(strings.TrimSpace(heightStr))
+	if err != nil || height < 1 || height > 255 {
+		return 0, 0, &TextParseError{line.num, InvalidTextSizeError}
+	}
+
+	return width, height, nil
+}
+
+type acrossLiteRebus struct {
+	value string // The full answer for the cell
+	short byte   // The letter stored in the solution board
+}
+
+// parseAcrossLiteRebus parses a <REBUS> section where each line is SYMBOL:ANSWER:SHORT, or MARK; to enable circles.
+func parseAcrossLiteRebus(section *acrossLiteSection) (bool, map[byte]acrossLiteRebus, error) {
+	rebuses := map[byte]acrossLiteRebus{}
+	markCircles := false
+
+	if section == nil {
+		return markCircles, rebuses, nil
+	}
+
+	for _, line := range section.lines {
+		if strings.ToUpper(line.text) == acrossLiteMarkCircles {
+			markCircles = true
+			continue
+		}
+
+		parts := strings.Split(line.text, ":")
+		if len(parts) != 3 || len(parts[0]) != 1 || len(parts[1]) == 0 || len(parts[2]) != 1 {
+			return false, nil, &TextParseError{line.num, InvalidTextRebusError}
+		}
+
+		symbol := parts[0][0]
+		if isLetter(symbol) || symbol == SolidSquare {
+			return false, nil, &TextParseError{line.num, InvalidTextRebusError}
+		}
+
+		rebuses[symbol] = acrossLiteRebus{strings.ToUpper(parts[1]), strings.ToUpper(parts[2])[0]}
+	}
+
+	return markCircles, rebuses, nil
+}
@@ -0,0 +1,219 @@
+package puz_test
+
+import (
+	"errors"
+	puz "github.com/cqb13/puz-parser"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sampleAcrossLiteText = `<ACROSS PUZZLE V2>
+<TITLE>
+	Sample
+<AUTHOR>
+	Tester
+<COPYRIGHT>
+	2026 Tester
+<SIZE>
+	3x3
+<GRID>
+	cAT
+	A1E
+	BE.
+<REBUS>
+	MARK;
+	1:HEART:H
+<ACROSS>
+	Pet
+	Love rebus
+	Sleep here
+<DOWN>
+	Taxi
+	Ape, maybe
+	Tee
+<NOTEPAD>
+	First line
+	Second line
+`
+
+func TestDecodeAcrossLiteText(t *testing.T) {
+	puzzle, err := puz.DecodeAcrossLiteText([]byte(sampleAcrossLiteText))
+	if err != nil {
+		t.Fatalf("Failed to decode text puzzle: %v", err)
+	}
+
+	if puzzle.Title != "Sample" || puzzle.Author != "Tester" || puzzle.Copyright != "2026 Tester" {
+		t.Fatalf("Failed to load metadata, found %q, %q, %q", puzzle.Title, puzzle.Author, puzzle.Copyright)
+	}
+
+	if puzzle.Notes != "First line\nSecond line" {
+		t.Fatalf("Failed to load notepad, found %q", puzzle.Notes)
+	}
+
+	if puzzle.Board[0][0].Answer != 'C' || puzzle.Board[0][0].Markup != puz.SquareCircled {
+		t.Fatalf("Failed to convert lowercase letter to a circled cell")
+	}
+
+	if puzzle.Board[1][1].Answer != 'H' || puzzle.Board[1][1].RebusKey == 0 || puzzle.Extras.RebusTable[0].Value != "HEART" {
+		t.Fatalf("Failed to convert rebus symbol")
+	}
+
+	clue, ok := puzzle.GetClueByNum(4, puz.Across)
+	if !ok || clue.Clue != "Love rebus" {
+		t.Fatalf("Failed to number clues from the grid")
+	}
+
+	clue, ok = puzzle.GetClueByNum(3, puz.Down)
+	if !ok || clue.Clue != "Tee" || clue.StartX != 2 || clue.StartY != 0 {
+		t.Fatalf("Failed to number clues from the grid")
+	}
+
+	if _, err := puz.EncodePuz(puzzle); err != nil {
+		t.Fatalf("Failed to encode converted puzzle: %v", err)
+	}
+}
+
+func TestDecodeAcrossLiteTextErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		line int
+		err  error
+	}{
+		{"missing header", "<TITLE>\n\tOops\n", 1, puz.MissingTextHeaderError},
+		{"unknown section", "<ACROSS PUZZLE>\n<TITLE>\n\tT\n<FOO>\n", 4, puz.UnknownTextSectionError},
+		{"rebus in v1", "<ACROSS PUZZLE>\n<REBUS>\n", 2, puz.TextSectionRequiresV2Error},
+		{"bad size", "<ACROSS PUZZLE>\n<TITLE>\n<AUTHOR>\n<COPYRIGHT>\n<SIZE>\n\t3by3\n<GRID>\n\tABC\n<ACROSS>\n<DOWN>\n", 6, puz.InvalidTextSizeError},
+		{"grid width", "<ACROSS PUZZLE>\n<TITLE>\n<AUTHOR>\n<COPYRIGHT>\n<SIZE>\n\t3x2\n<GRID>\n\tABC\n\tAB\n<ACROSS>\n<DOWN>\n", 9, puz.TextGridSizeMismatchError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := puz.DecodeAcrossLiteText([]byte(tc.text))
+
+			var parseErr *puz.TextParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expected a TextParseError, found %v", err)
+			}
+
+			if parseErr.Line != tc.line {
+				t.Errorf("Expected error on line %d, found line %d", tc.line, parseErr.Line)
+			}
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Expected %v, found %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestDecodeAcrossLiteTextClueCount(t *testing.T) {
+	text := "<ACROSS PUZZLE>\n<TITLE>\n<AUTHOR>\n<COPYRIGHT>\n<SIZE>\n\t2x2\n<GRID>\n\tAB\n\tCD\n<ACROSS>\n\tOne\n<DOWN>\n\tOne\n\tTwo\n"
+
+	_, err := puz.DecodeAcrossLiteText([]byte(text))
+
+	var countErr *puz.ClueCountMismatchError
+	if !errors.As(err, &countErr) {
+		t.Fatalf("Expected a ClueCountMismatchError, found %v", err)
+	}
+}
+
+func TestAcrossLiteTextEmptyClue(t *testing.T) {
+	original, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	clues := original.Clues()
+	clues[0].Clue = ""
+	original.SetClues(clues)
+
+	data, warnings, err := puz.EncodeAcrossLiteText(original)
+	if err != nil {
+		t.Fatalf("Failed to encode as text: %v", err)
+	}
+
+	found := slices.ContainsFunc(warnings, func(w puz.ConversionWarning) bool {
+		return w.Feature == "clues"
+	})
+	if !found {
+		t.Fatalf("Expected a clues warning, found %v", warnings)
+	}
+
+	converted, err := puz.DecodeAcrossLiteText(data)
+	if err != nil {
+		t.Fatalf("Failed to decode text: %v\n%s", err, data)
+	}
+
+	if !slices.Equal(converted.Clues(), original.Clues()) {
+		t.Fatalf("Expected the empty clue to round trip, found %v", converted.Clues())
+	}
+
+	// a clue that is only the placeholder can not be told apart from an empty clue
+	clues[0].Clue = "-"
+	original.SetClues(clues)
+
+	_, warnings, err = puz.EncodeAcrossLiteText(original)
+	if err != nil {
+		t.Fatalf("Failed to encode as text: %v", err)
+	}
+
+	found = slices.ContainsFunc(warnings, func(w puz.ConversionWarning) bool {
+		return w.Feature == "clues" && strings.Contains(w.Message, "read back")
+	})
+	if !found {
+		t.Fatalf("Expected a clues warning for the placeholder clue, found %v", warnings)
+	}
+}
+
+func TestAcrossLiteTextRoundTrip(t *testing.T) {
+	testCases := []string{
+		"Crossword.puz",
+		"Crossword-EXT-Rebus.puz",
+		"NYT-Nov2193.puz",
+		"washpost.puz",
+	}
+
+	for _, name := range testCases {
+		t.Run(name, func(t *testing.T) {
+			original, err := puz.DecodePuz(loadFile(t, name))
+			if err != nil {
+				t.Fatalf("Failed to decode %s: %v", name, err)
+			}
+
+			data, _, err := puz.EncodeAcrossLiteText(original)
+			if err != nil {
+				t.Fatalf("Failed to encode %s as text: %v", name, err)
+			}
+
+			converted, err := puz.DecodeAcrossLiteText(data)
+			if err != nil {
+				t.Fatalf("Failed to decode text for %s: %v\n%s", name, err, data)
+			}
+
+			if converted.Title != original.Title || converted.Author != original.Author || converted.Copyright != original.Copyright || converted.Notes != original.Notes {
+				t.Errorf("Strings changed during text round trip")
+			}
+
+			for y := range original.Board {
+				for x := range original.Board[y] {
+					a := original.Board[y][x]
+					b := converted.Board[y][x]
+
+					if a.Answer != b.Answer || (a.RebusKey == 0) != (b.RebusKey == 0) || a.Markup != b.Markup {
+						t.Fatalf("Cell x: %d y: %d changed during text round trip", x, y)
+					}
+				}
+			}
+
+			if !slices.Equal(original.Clues(), converted.Clues()) {
+				t.Errorf("Clues changed during text round trip")
+			}
+
+			if _, err := puz.EncodePuz(converted); err != nil {
+				t.Fatalf("Failed to encode converted %s: %v", name, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package puz_test
+
+import (
+	puz "github.com/cqb13/puz-parser"
+	"strings"
+	"testing"
+)
+
+func TestRebusAnswers(t *testing.T) {
+	name := "NYT-Nov2193.puz"
+
+	puzzle, err := puz.DecodePuz(loadFile(t, name))
+	if err != nil {
+		t.Fatalf("Failed to decode %s: %v", name, err)
+	}
+
+	boardWords := puzzle.Board.GetWords()
+	words := puzzle.GetWords()
+
+	if len(boardWords) != len(words) {
+		t.Fatalf("Expected %d words, found %d", len(boardWords), len(words))
+	}
+
+	expanded := 0
+
+	for i, word := range words {
+		if len(word.Word) > len(boardWords[i].Word) {
+			expanded++
+		}
+
+		if word.Word != puzzle.WordAnswer(boardWords[i]) {
+			t.Errorf("GetWords and WordAnswer differ for %d %v", word.Num, word.Direction)
+		}
+	}
+
+	if expanded == 0 {
+		t.Fatalf("Expected rebus words to be expanded")
+	}
+
+	for y := range puzzle.Board {
+		for x := range puzzle.Board[y] {
+			value, ok := puzzle.Rebus(x, y)
+			answer := puzzle.Answer(x, y)
+
+			if ok && answer != value {
+				t.Fatalf("Expected %s at x: %d y: %d, found %s", value, x, y, answer)
+			}
+
+			if !ok && answer != string(puzzle.Board[y][x].Answer) {
+				t.Fatalf("Expected %c at x: %d y: %d, found %s", puzzle.Board[y][x].Answer, x, y, answer)
+			}
+		}
+	}
+}
+
+func TestWordGuess(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	puzzle.SetRebus(0, 0, "HEART")
+	puzzle.SetUserRebus(0, 0, "HEARD")
+
+	word := puzzle.GetWords()[0]
+
+	if !strings.HasPrefix(word.Word, "HEART") {
+		t.Fatalf("Expected the first word to start with HEART, found %s", word.Word)
+	}
+
+	if guess := puzzle.WordGuess(word); !strings.HasPrefix(guess, "HEARD") {
+		t.Fatalf("Expected the guess to start with HEARD, found %s", guess)
+	}
+
+	if puzzle.Guess(0, 0) != "HEARD" || puzzle.Answer(0, 0) != "HEART" {
+		t.Fatalf("Expected HEARD and HEART, found %s and %s", puzzle.Guess(0, 0), puzzle.Answer(0, 0))
+	}
+
+	if puzzle.Answer(-1, 0) != "" || puzzle.Guess(0, 100) != "" {
+		t.Fatalf("Expected empty strings outside of the board")
+	}
+}
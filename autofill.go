@@ -0,0 +1,272 @@
+package puz
+
+import (
+	"context"
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// FillWord is a word that autofill can place in the grid
+type FillWord struct {
+	Word  string // The word, it is uppercased before use
+	Score int    // Higher scoring words are tried first
+}
+
+// AutofillOptions controls Autofill
+type AutofillOptions struct {
+	Words      []FillWord // The word list used to fill open slots
+	Index      *WordIndex // An index used instead of Words, so one index can be shared between calls
+	MaxResults int        // The number of highest scoring fills to return, defaults to 1
+}
+
+// Fill is a completed grid found by Autofill
+type Fill struct {
+	Board Board // A copy of the board with every open cell filled
+	Score int   // The sum of the scores of the words placed by autofill
+}
+
+// Autofill fills every open cell on board, cells with an EmptySolutionSquare answer, with words from opts.Index or opts.Words.
+//
+// Words that are already complete in the grid are kept as is and can not be used again.
+// Slots are filled most constrained first, trying higher scoring words first and backtracking on dead ends.
+// The search keeps the opts.MaxResults highest scoring fills, returned highest score first, and skips branches that can not beat them.
+// Large grids can take a long time to search completely, use a ctx with a deadline to bound the search.
+// If ctx is done before the search finishes the best fills found so far are returned with the context error.
+// Returns NoFillFoundError if the search finishes without a fill.
+func Autofill(ctx context.Context, board Board, opts AutofillOptions) ([]Fill, error) {
+	maxResults := opts.MaxResults
+	if maxResults <= 0 {
+		maxResults = 1
+	}
+
+	index := opts.Index
+	if index == nil {
+		index = NewWordIndex(opts.Words)
+	}
+
+	f := newFiller(board, index, maxResults)
+	f.solve(ctx)
+
+	if err := ctx.Err(); err != nil {
+		return f.results, err
+	}
+
+	if len(f.results) == 0 {
+		return nil, NoFillFoundError
+	}
+
+	return f.results, nil
+}
+
+type filler struct {
+	board      Board
+	slots      [][]Position
+	assigned   []bool
+	used       map[string]bool
+	index      *WordIndex
+	score      int
+	results    []Fill
+	maxResults int
+}
+
+func newFiller(board Board, index *WordIndex, maxResults int) *filler {
+	f := filler{
+		cloneBoard(board),
+		nil,
+		nil,
+		make(map[string]bool),
+		index,
+		0,
+		nil,
+		maxResults,
+	}
+
+	slots := NewSlots(board)
+	for _, word := range slots.Words() {
+		f.slots = append(f.slots, slots.Cells(word))
+	}
+
+	f.assigned = make([]bool, len(f.slots))
+
+	// complete words are theme entries
+	for i := range f.slots {
+		if f.isComplete(i) {
+			f.assigned[i] = true
+			f.used[f.slotText(i)] = true
+		}
+	}
+
+	return &f
+}
+
+func cloneBoard(board Board) Board {
+	clone := make(Board, len(board))
+
+	for y := range board {
+		clone[y] = slices.Clone(board[y])
+	}
+
+	return clone
+}
+
+func (f *filler) isOpen(pos Position) bool {
+	return f.board[pos.Y][pos.X].Answer == EmptySolutionSquare
+}
+
+func (f *filler) isComplete(slot int) bool {
+	for _, pos := range f.slots[slot] {
+		if f.isOpen(pos) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *filler) slotText(slot int) string {
+	var text strings.Builder
+
+	for _, pos := range f.slots[slot] {
+		text.WriteByte(f.board[pos.Y][pos.X].Answer)
+	}
+
+	return text.String()
+}
+
+// candidates returns the set of words that match the letters already in slot.
+func (f *filler) candidates(slot int) (*fillIndex, []uint64) {
+	cells := f.slots[slot]
+
+	return f.index.lookup(len(cells), func(i int) (byte, bool) {
+		pos := cells[i]
+		return f.board[pos.Y][pos.X].Answer, !f.isOpen(pos)
+	})
+}
+
+// solve fills the most constrained unassigned slot with each of its candidates in score order.
+//
+// Branches are skipped once opts.MaxResults fills are kept and the best score a branch can reach does not beat the lowest kept fill.
+// The best reachable score adds the highest scoring candidate of every unassigned slot to the current score.
+func (f *filler) solve(ctx context.Context) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	best := -1
+	bestCount := 0
+	bestMax := 0
+	bound := 0
+	var bestIndex *fillIndex
+	var bestSet []uint64
+
+	for slot := range f.slots {
+		if f.assigned[slot] {
+			continue
+		}
+
+		index, set := f.candidates(slot)
+		count := countSet(set)
+
+		if count == 0 {
+			return
+		}
+
+		// candidates are sorted by score so the first is the highest
+		top := index.words[firstInSet(set)].Score
+		bound += top
+
+		if best == -1 || count < bestCount {
+			best = slot
+			bestCount = count
+			bestMax = top
+			bestIndex = index
+			bestSet = set
+		}
+	}
+
+	if !f.canImprove(f.score + bound) {
+		return
+	}
+
+	if best == -1 {
+		f.keep(Fill{cloneBoard(f.board), f.score})
+		return
+	}
+
+	rest := bound - bestMax
+
+	for block, word := range bestSet {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			word &^= 1 << bit
+
+			candidate := bestIndex.words[block*64+bit]
+
+			// later candidates score lower, so none of them can improve either
+			if !f.canImprove(f.score + candidate.Score + rest) {
+				return
+			}
+
+			if f.used[candidate.Word] {
+				continue
+			}
+
+			placed := f.place(best, candidate)
+			f.solve(ctx)
+			f.unplace(best, candidate, placed)
+
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}
+}
+
+// canImprove reports if a fill scoring score would be kept.
+func (f *filler) canImprove(score int) bool {
+	return len(f.results) < f.maxResults || score > f.results[len(f.results)-1].Score
+}
+
+// keep adds fill to the results, which stay sorted highest score first and hold at most maxResults fills.
+func (f *filler) keep(fill Fill) {
+	i := len(f.results)
+	for i > 0 && f.results[i-1].Score < fill.Score {
+		i--
+	}
+
+	f.results = slices.Insert(f.results, i, fill)
+
+	if len(f.results) > f.maxResults {
+		f.results = f.results[:f.maxResults]
+	}
+}
+
+// place writes word into slot and returns the cells that were open.
+// Words come from a WordIndex, so they never hold a marker like EmptySolutionSquare that would leave a cell open.
+func (f *filler) place(slot int, word FillWord) []Position {
+	var placed []Position
+
+	for i, pos := range f.slots[slot] {
+		if f.isOpen(pos) {
+			f.board[pos.Y][pos.X].Answer = word.Word[i]
+			placed = append(placed, pos)
+		}
+	}
+
+	f.assigned[slot] = true
+	f.used[word.Word] = true
+	f.score += word.Score
+
+	return placed
+}
+
+func (f *filler) unplace(slot int, word FillWord, placed []Position) {
+	for _, pos := range placed {
+		f.board[pos.Y][pos.X].Answer = EmptySolutionSquare
+	}
+
+	f.assigned[slot] = false
+	delete(f.used, word.Word)
+	f.score -= word.Score
+}
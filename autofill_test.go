@@ -0,0 +1,245 @@
+package puz_test
+
+import (
+	"context"
+	"errors"
+	puz "github.com/cqb13/puz-parser"
+	"strings"
+	"testing"
+)
+
+func autofillBoard(t *testing.T, rows ...string) puz.Board {
+	t.Helper()
+
+	// '-' marks an open cell
+	var arr [][]byte
+	for _, row := range rows {
+		arr = append(arr, []byte(strings.ReplaceAll(row, "-", string(puz.EmptySolutionSquare))))
+	}
+
+	board, err := puz.NewBoardFromArr(arr)
+	if err != nil {
+		t.Fatalf("Failed to create board: %v", err)
+	}
+
+	return board
+}
+
+func TestAutofillBacktracks(t *testing.T) {
+	board := autofillBoard(t, "BASS.", "-----", "STONE", "HORSE", ".REED")
+
+	words := []puz.FillWord{
+		{Word: "achex", Score: 100},
+		{Word: "ACHED", Score: 50},
+		{Word: "BASH", Score: 1},
+		{Word: "ACTOR", Score: 1},
+		{Word: "SHORE", Score: 1},
+		{Word: "SENSE", Score: 1},
+		{Word: "DEED", Score: 1},
+	}
+
+	fills, err := puz.Autofill(context.Background(), board, puz.AutofillOptions{Words: words})
+	if err != nil {
+		t.Fatalf("Failed to autofill board: %v", err)
+	}
+
+	if len(fills) != 1 {
+		t.Fatalf("Expected 1 fill, found %d", len(fills))
+	}
+
+	if word, _ := fills[0].Board.GetWord(0, 1, puz.Across); word != "ACHED" {
+		t.Fatalf("Expected ACHED, found %s", word)
+	}
+
+	if fills[0].Score != 55 {
+		t.Fatalf("Expected a score of 55, found %d", fills[0].Score)
+	}
+
+	if board[1][0].Answer != puz.EmptySolutionSquare {
+		t.Fatalf("Expected the original board to be unchanged")
+	}
+}
+
+func TestAutofillExcludesGridWords(t *testing.T) {
+	board := autofillBoard(t, "AB", "--")
+
+	words := []puz.FillWord{
+		{Word: "AB", Score: 100},
+		{Word: "CD", Score: 1},
+		{Word: "AC", Score: 1},
+		{Word: "BD", Score: 1},
+	}
+
+	fills, err := puz.Autofill(context.Background(), board, puz.AutofillOptions{Words: words, MaxResults: 5})
+	if err != nil {
+		t.Fatalf("Failed to autofill board: %v", err)
+	}
+
+	if len(fills) != 1 {
+		t.Fatalf("Expected 1 fill, found %d", len(fills))
+	}
+
+	if word, _ := fills[0].Board.GetWord(0, 1, puz.Across); word != "CD" {
+		t.Fatalf("Expected CD, found %s", word)
+	}
+}
+
+func TestAutofillTopResults(t *testing.T) {
+	board := autofillBoard(t, "--", "--")
+
+	words := []puz.FillWord{
+		{Word: "AB", Score: 10},
+		{Word: "CD", Score: 10},
+		{Word: "AC", Score: 10},
+		{Word: "BD", Score: 10},
+		{Word: "EF", Score: 1},
+		{Word: "GH", Score: 1},
+		{Word: "EG", Score: 1},
+		{Word: "FH", Score: 1},
+	}
+
+	fills, err := puz.Autofill(context.Background(), board, puz.AutofillOptions{Words: words, MaxResults: 3})
+	if err != nil {
+		t.Fatalf("Failed to autofill board: %v", err)
+	}
+
+	if len(fills) != 3 {
+		t.Fatalf("Expected 3 fills, found %d", len(fills))
+	}
+
+	if fills[0].Score != 40 {
+		t.Fatalf("Expected the best fill to score 40, found %d", fills[0].Score)
+	}
+
+	for i := 1; i < len(fills); i++ {
+		if fills[i].Score > fills[i-1].Score {
+			t.Fatalf("Expected fills sorted by score, found %d after %d", fills[i].Score, fills[i-1].Score)
+		}
+	}
+}
+
+func TestAutofillBestFillFoundLater(t *testing.T) {
+	board := autofillBoard(t, "---", "---", "---")
+
+	// ABC is tried first but its fill only scores 15, the fill using the 9 point words scores 54
+	words := []puz.FillWord{
+		{Word: "ABC", Score: 10},
+		{Word: "DEF", Score: 1},
+		{Word: "GHI", Score: 1},
+		{Word: "ADG", Score: 1},
+		{Word: "BEH", Score: 1},
+		{Word: "CFI", Score: 1},
+		{Word: "JKL", Score: 9},
+		{Word: "MNO", Score: 9},
+		{Word: "PQR", Score: 9},
+		{Word: "JMP", Score: 9},
+		{Word: "KNQ", Score: 9},
+		{Word: "LOR", Score: 9},
+	}
+
+	fills, err := puz.Autofill(context.Background(), board, puz.AutofillOptions{Words: words})
+	if err != nil {
+		t.Fatalf("Failed to autofill board: %v", err)
+	}
+
+	if len(fills) != 1 || fills[0].Score != 54 {
+		t.Fatalf("Expected the fill scoring 54, found %v", fills)
+	}
+
+	fills, err = puz.Autofill(context.Background(), board, puz.AutofillOptions{Words: words, MaxResults: 10})
+	if err != nil {
+		t.Fatalf("Failed to autofill board: %v", err)
+	}
+
+	// both word sets also fit with the rows and columns swapped
+	scores := []int{54, 54, 15, 15}
+	if len(fills) != len(scores) {
+		t.Fatalf("Expected %d fills, found %d", len(scores), len(fills))
+	}
+
+	for i, fill := range fills {
+		if fill.Score != scores[i] {
+			t.Fatalf("Expected fill %d to score %d, found %d", i, scores[i], fill.Score)
+		}
+	}
+}
+
+func TestAutofillSkipsMarkerWords(t *testing.T) {
+	board := autofillBoard(t, "--", "--")
+
+	// "A " would leave its second cell open for the crossing words to fill
+	words := []puz.FillWord{
+		{Word: "A ", Score: 100},
+		{Word: "AB", Score: 1},
+		{Word: "AC", Score: 1},
+		{Word: "BD", Score: 1},
+		{Word: "CD", Score: 1},
+	}
+
+	fills, err := puz.Autofill(context.Background(), board, puz.AutofillOptions{Words: words})
+	if err != nil {
+		t.Fatalf("Failed to autofill board: %v", err)
+	}
+
+	if len(fills) != 1 || fills[0].Score != 4 {
+		t.Fatalf("Expected the fill scoring 4, found %v", fills)
+	}
+
+	if index := puz.NewWordIndex(words); index.Len() != 4 {
+		t.Fatalf("Expected 4 indexed words, found %d", index.Len())
+	}
+}
+
+func TestAutofillNoFill(t *testing.T) {
+	board := autofillBoard(t, "BASS.", "-----", "STONE", "HORSE", ".REED")
+
+	words := []puz.FillWord{{Word: "ACHED", Score: 1}}
+
+	_, err := puz.Autofill(context.Background(), board, puz.AutofillOptions{Words: words})
+	if !errors.Is(err, puz.NoFillFoundError) {
+		t.Fatalf("Expected NoFillFoundError, found %v", err)
+	}
+}
+
+func TestAutofillCancelled(t *testing.T) {
+	board := autofillBoard(t, "--", "--")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := puz.Autofill(ctx, board, puz.AutofillOptions{Words: []puz.FillWord{{Word: "AB", Score: 1}}})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, found %v", err)
+	}
+}
+
+func TestWordIndex(t *testing.T) {
+	index := puz.NewWordIndex([]puz.FillWord{
+		{Word: "horse", Score: 50},
+		{Word: "SHORE", Score: 70},
+		{Word: "HORSE", Score: 10},
+		{Word: "STONE", Score: 55},
+	})
+
+	if index.Len() != 3 {
+		t.Fatalf("Expected 3 words, found %d", index.Len())
+	}
+
+	matches := index.Match("?ho?e")
+	if len(matches) != 1 || matches[0] != (puz.FillWord{Word: "SHORE", Score: 70}) {
+		t.Fatalf("Found unexpected matches %v", matches)
+	}
+
+	matches = index.Match("?????")
+	if len(matches) != 3 || matches[2] != (puz.FillWord{Word: "HORSE", Score: 50}) {
+		t.Fatalf("Found unexpected matches %v", matches)
+	}
+
+	board := autofillBoard(t, "AB", "--")
+	words := []puz.FillWord{{Word: "CD", Score: 1}, {Word: "AC", Score: 1}, {Word: "BD", Score: 1}}
+
+	fills, err := puz.Autofill(context.Background(), board, puz.AutofillOptions{Index: puz.NewWordIndex(words)})
+	if err != nil || len(fills) != 1 {
+		t.Fatalf("Failed to autofill board from an index: %v", err)
+	}
+}
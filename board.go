@@ -15,12 +15,42 @@ type Word struct {
 	Direction Direction // The direction of the word
 }
 
+// A Position is the location of a cell on the board
+type Position struct {
+	X int
+	Y int
+}
+
 // A Cell is a square in a crossword grid
 type Cell struct {
-	Answer   byte // The answer letter
-	Guess    byte // The letter guessed by the player (used for saving game state)
-	RebusKey byte // Indicates a connection to a value in the rebus table with the same key
-	Markup   byte // Indicates applied markup
+	Answer   byte         // The answer letter
+	Guess    byte         // The letter guessed by the player (used for saving game state)
+	RebusKey byte         // Indicates a connection to a value in the rebus table with the same key
+	Markup   MarkupSquare // Indicates applied markup, unknown bits are preserved
+}
+
+// HasMarkup reports if every bit in markup is set on the cell.
+func (c Cell) HasMarkup(markup MarkupSquare) bool {
+	return c.Markup&markup == markup
+}
+
+// SetMarkup sets or clears the bits in markup, other bits are left unchanged.
+func (c *Cell) SetMarkup(markup MarkupSquare, set bool) {
+	if set {
+		c.Markup |= markup
+	} else {
+		c.Markup &^= markup
+	}
+}
+
+// IsCircled reports if the cell is circled.
+func (c Cell) IsCircled() bool {
+	return c.HasMarkup(SquareCircled)
+}
+
+// SetCircled circles or uncircles the cell.
+func (c *Cell) SetCircled(circled bool) {
+	c.SetMarkup(SquareCircled, circled)
 }
 
 // NewBoard returns a Board of width x height.
@@ -102,6 +132,54 @@ func (b Board) inBounds(x int, y int) bool {
 	return false
 }
 
+// cells returns the positions of every cell in reading order.
+func (b Board) cells() []Position {
+	var cells []Position
+
+	for y := range b.Height() {
+		for x := range b.Width() {
+			cells = append(cells, Position{x, y})
+		}
+	}
+
+	return cells
+}
+
+// whiteRegions returns each group of white squares connected across or down, in reading order of their first square.
+func (b Board) whiteRegions() [][]Position {
+	var regions [][]Position
+	seen := make(map[Position]bool)
+
+	for _, start := range b.cells() {
+		if seen[start] || b.IsSolidSquare(start.X, start.Y) {
+			continue
+		}
+
+		var region []Position
+		queue := []Position{start}
+		seen[start] = true
+
+		for len(queue) > 0 {
+			pos := queue[0]
+			queue = queue[1:]
+			region = append(region, pos)
+
+			for _, next := range []Position{{pos.X + 1, pos.Y}, {pos.X - 1, pos.Y}, {pos.X, pos.Y + 1}, {pos.X, pos.Y - 1}} {
+				if !b.inBounds(next.X, next.Y) || b.IsSolidSquare(next.X, next.Y) || seen[next] {
+					continue
+				}
+
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+
+		regions = append(regions, region)
+	}
+
+	return regions
+}
+
 // IsSolidSquare reports if a cell at (x, y) is SOLID_SQUARE or DiagramlessSolidSquare.
 func (b Board) IsSolidSquare(x int, y int) bool {
 	if !b.inBounds(x, y) {
@@ -111,6 +189,61 @@ func (b Board) IsSolidSquare(x int, y int) bool {
 	return b[y][x].Answer == SolidSquare || b[y][x].Answer == DiagramlessSolidSquare
 }
 
+// IsRebus reports if the cell at (x, y) contains a rebus.
+func (b Board) IsRebus(x int, y int) bool {
+	if !b.inBounds(x, y) {
+		return false
+	}
+
+	return b[y][x].RebusKey != 0
+}
+
+// HasRebus reports if any cell on the board contains a rebus.
+func (b Board) HasRebus() bool {
+	for y := range b {
+		for x := range b[y] {
+			if b[y][x].RebusKey != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// HasMarkup reports if any cell on the board has markup.
+func (b Board) HasMarkup() bool {
+	for y := range b {
+		for x := range b[y] {
+			if b[y][x].Markup != None {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// CellsWithMarkup returns the positions of cells with every bit in markup set, in reading order.
+func (b Board) CellsWithMarkup(markup MarkupSquare) []Position {
+	var cells []Position
+
+	for y := range b {
+		for x := range b[y] {
+			if b[y][x].HasMarkup(markup) {
+				cells = append(cells, Position{x, y})
+			}
+		}
+	}
+
+	return cells
+}
+
+// CircledCells returns the positions of circled cells, in reading order.
+func (b Board) CircledCells() []Position {
+	return b.CellsWithMarkup(SquareCircled)
+}
+
 // GetWord returns the series of letters starting at (x, y) in the given direction. Continues until the edge of the board or until a solid square is hit.
 //
 // The word is only valid if the bool is true.
@@ -187,7 +320,22 @@ func (b Board) StartsDownWord(x int, y int) bool {
 	return false
 }
 
+// cellNumbers returns the clue number for every cell indexed as [y][x], 0 for cells that do not start a word.
+func (b Board) cellNumbers() [][]int {
+	numbers := make([][]int, b.Height())
+	for y := range b.Height() {
+		numbers[y] = make([]int, b.Width())
+	}
+
+	for _, word := range b.GetWords() {
+		numbers[word.StartY][word.StartX] = word.Num
+	}
+
+	return numbers
+}
+
 // GetWords returns a list of Words from the board.
+// Rebus cells only contribute their first letter, use Puzzle.GetWords for full answers.
 func (b Board) GetWords() []Word {
 	var words []Word
 
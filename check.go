@@ -0,0 +1,179 @@
+package puz
+
+import (
+	"strings"
+)
+
+// CheckCell checks the players guess for the cell at (x, y).
+//
+// A wrong guess is marked CurrentlyIncorrect, a corrected guess that was marked incorrect is changed to PreviouslyIncorrect.
+// Empty cells are not checked. Returns the cell if it was marked either way, use Cell.HasMarkup to tell them apart.
+func (p *Puzzle) CheckCell(x int, y int) ([]Position, error) {
+	if !p.Board.inBounds(x, y) {
+		return nil, OutOfBoundsWriteError
+	}
+
+	return p.checkCells([]Position{{x, y}})
+}
+
+// CheckWord checks every cell in word, returns the incorrect cells and the corrected cells changed to PreviouslyIncorrect.
+func (p *Puzzle) CheckWord(word Word) ([]Position, error) {
+	if !p.Board.inBounds(word.StartX, word.StartY) {
+		return nil, OutOfBoundsWriteError
+	}
+
+	return p.checkCells(p.wordCells(word))
+}
+
+// CheckPuzzle checks every cell on the board, returns the incorrect cells and the corrected cells changed to PreviouslyIncorrect.
+func (p *Puzzle) CheckPuzzle() ([]Position, error) {
+	return p.checkCells(p.Board.cells())
+}
+
+// RevealCell sets the guess for the cell at (x, y) to the answer and marks it ContentGiven.
+//
+// Cells that are already correct are left unchanged, an incorrect guess is also marked PreviouslyIncorrect.
+// A rebus cell guessed by only its first letter is revealed to the full rebus without being marked incorrect.
+// Returns the cell if it was revealed.
+func (p *Puzzle) RevealCell(x int, y int) ([]Position, error) {
+	if !p.Board.inBounds(x, y) {
+		return nil, OutOfBoundsWriteError
+	}
+
+	return p.revealCells([]Position{{x, y}})
+}
+
+// RevealWord reveals every cell in word, returns the revealed cells.
+func (p *Puzzle) RevealWord(word Word) ([]Position, error) {
+	if !p.Board.inBounds(word.StartX, word.StartY) {
+		return nil, OutOfBoundsWriteError
+	}
+
+	return p.revealCells(p.wordCells(word))
+}
+
+// RevealPuzzle reveals every cell on the board, returns the revealed cells.
+func (p *Puzzle) RevealPuzzle() ([]Position, error) {
+	return p.revealCells(p.Board.cells())
+}
+
+// guessIsCorrect compares the guess for a cell to its answer, ignoring case.
+//
+// When the player has entered a rebus the full values are compared, otherwise only the first letter of a rebus is needed.
+func (p *Puzzle) guessIsCorrect(x int, y int) bool {
+	cell := p.Board[y][x]
+
+	if guess, ok := p.UserRebus(x, y); ok {
+		return strings.EqualFold(guess, p.Answer(x, y))
+	}
+
+	return strings.EqualFold(string([]byte{cell.Guess}), string([]byte{cell.Answer}))
+}
+
+// isEmptyGuess reports if the player has not entered anything in the cell at (x, y).
+func (p *Puzzle) isEmptyGuess(x int, y int) bool {
+	guess := p.Board[y][x].Guess
+	return guess == EmptyStateSquare || guess == EmptySolutionSquare || guess == 0x00
+}
+
+func (p *Puzzle) checkCells(cells []Position) ([]Position, error) {
+	if p.Scrambled() {
+		return nil, PuzzleIsScrambledError
+	}
+
+	var marked []Position
+
+	for _, pos := range cells {
+		if p.Board.IsSolidSquare(pos.X, pos.Y) || p.isEmptyGuess(pos.X, pos.Y) {
+			continue
+		}
+
+		cell := &p.Board[pos.Y][pos.X]
+
+		if p.guessIsCorrect(pos.X, pos.Y) {
+			if cell.HasMarkup(CurrentlyIncorrect) {
+				cell.SetMarkup(CurrentlyIncorrect, false)
+				cell.SetMarkup(PreviouslyIncorrect, true)
+				marked = append(marked, pos)
+			}
+
+			continue
+		}
+
+		cell.SetMarkup(CurrentlyIncorrect, true)
+		marked = append(marked, pos)
+	}
+
+	if len(marked) > 0 {
+		p.AddExtraSection(MarkupBoardSection)
+	}
+
+	return marked, nil
+}
+
+func (p *Puzzle) revealCells(cells []Position) ([]Position, error) {
+	if p.Scrambled() {
+		return nil, PuzzleIsScrambledError
+	}
+
+	var revealed []Position
+
+	for _, pos := range cells {
+		if p.Board.IsSolidSquare(pos.X, pos.Y) {
+			continue
+		}
+
+		empty := p.isEmptyGuess(pos.X, pos.Y)
+		correct := !empty && p.guessIsCorrect(pos.X, pos.Y)
+
+		// a rebus guessed by its first letter is correct but is still revealed in full
+		if correct && strings.EqualFold(p.Guess(pos.X, pos.Y), p.Answer(pos.X, pos.Y)) {
+			continue
+		}
+
+		cell := &p.Board[pos.Y][pos.X]
+
+		if !empty && !correct {
+			cell.SetMarkup(CurrentlyIncorrect, false)
+			cell.SetMarkup(PreviouslyIncorrect, true)
+		}
+
+		cell.Guess = cell.Answer
+		cell.SetMarkup(ContentGiven, true)
+
+		if value, ok := p.Rebus(pos.X, pos.Y); ok {
+			err := p.SetUserRebus(pos.X, pos.Y, value)
+			if err != nil {
+				return revealed, err
+			}
+		}
+
+		revealed = append(revealed, pos)
+	}
+
+	if len(revealed) > 0 {
+		p.AddExtraSection(MarkupBoardSection)
+	}
+
+	return revealed, nil
+}
+
+// wordCells returns the positions of the cells in word.
+func (p *Puzzle) wordCells(word Word) []Position {
+	var cells []Position
+
+	x := word.StartX
+	y := word.StartY
+
+	for p.Board.inBounds(x, y) && !p.Board.IsSolidSquare(x, y) {
+		cells = append(cells, Position{x, y})
+
+		if word.Direction == Across {
+			x++
+		} else {
+			y++
+		}
+	}
+
+	return cells
+}
@@ -0,0 +1,218 @@
+package puz_test
+
+import (
+	puz "github.com/cqb13/puz-parser"
+	"slices"
+	"testing"
+)
+
+func loadUnsolved(t *testing.T, name string) *puz.Puzzle {
+	t.Helper()
+
+	puzzle, err := puz.DecodePuz(loadFile(t, name))
+	if err != nil {
+		t.Fatalf("Failed to decode %s: %v", name, err)
+	}
+
+	for y := range puzzle.Board {
+		for x := range puzzle.Board[y] {
+			if !puzzle.Board.IsSolidSquare(x, y) {
+				puzzle.Board[y][x].Guess = puz.EmptyStateSquare
+			}
+			puzzle.Board[y][x].Markup = 0
+		}
+	}
+
+	puzzle.RemoveExtraSection(puz.MarkupBoardSection)
+
+	return puzzle
+}
+
+func TestCheckCell(t *testing.T) {
+	puzzle := loadUnsolved(t, "Crossword.puz")
+
+	incorrect, err := puzzle.CheckCell(0, 0)
+	if err != nil || len(incorrect) != 0 {
+		t.Fatalf("Expected an empty cell to not be checked, found %v, %v", incorrect, err)
+	}
+
+	answer := puzzle.Board[0][0].Answer
+	puzzle.Board[0][0].Guess = 'Z'
+	if answer == 'Z' {
+		puzzle.Board[0][0].Guess = 'Y'
+	}
+
+	incorrect, err = puzzle.CheckCell(0, 0)
+	if err != nil {
+		t.Fatalf("Failed to check cell: %v", err)
+	}
+
+	if !slices.Equal(incorrect, []puz.Position{{X: 0, Y: 0}}) {
+		t.Fatalf("Expected the cell to be incorrect, found %v", incorrect)
+	}
+
+	if puzzle.Board[0][0].Markup != puz.CurrentlyIncorrect || !puzzle.HasExtraSection(puz.MarkupBoardSection) {
+		t.Fatalf("Expected the cell to be marked incorrect")
+	}
+
+	// fixing the guess moves the mark to previously incorrect
+	puzzle.Board[0][0].Guess = answer | 0x20
+
+	marked, _ := puzzle.CheckCell(0, 0)
+	if !slices.Equal(marked, []puz.Position{{X: 0, Y: 0}}) {
+		t.Fatalf("Expected the corrected cell to be returned, found %v", marked)
+	}
+
+	if puzzle.Board[0][0].Markup != puz.PreviouslyIncorrect {
+		t.Fatalf("Expected the cell to be marked previously incorrect, found %x", puzzle.Board[0][0].Markup)
+	}
+
+	// a cell that is already previously incorrect does not change again
+	if marked, _ = puzzle.CheckCell(0, 0); len(marked) != 0 {
+		t.Fatalf("Expected a lowercase correct guess to be correct, found %v", marked)
+	}
+
+	if _, err := puzzle.CheckCell(100, 0); err != puz.OutOfBoundsWriteError {
+		t.Fatalf("Expected OutOfBoundsWriteError, found %v", err)
+	}
+}
+
+func TestCheckWordAndPuzzle(t *testing.T) {
+	puzzle := loadUnsolved(t, "Crossword.puz")
+	word := puzzle.Board.GetWords()[0]
+
+	for i := range len(word.Word) {
+		puzzle.Board[word.StartY][word.StartX+i].Guess = 'Q'
+	}
+
+	incorrect, err := puzzle.CheckWord(word)
+	if err != nil {
+		t.Fatalf("Failed to check word: %v", err)
+	}
+
+	if len(incorrect) != len(word.Word) {
+		t.Fatalf("Expected %d incorrect cells, found %v", len(word.Word), incorrect)
+	}
+
+	all, err := puzzle.CheckPuzzle()
+	if err != nil {
+		t.Fatalf("Failed to check puzzle: %v", err)
+	}
+
+	if !slices.Equal(all, incorrect) {
+		t.Fatalf("Expected only the word to be incorrect, found %v", all)
+	}
+}
+
+func TestRevealCell(t *testing.T) {
+	puzzle := loadUnsolved(t, "Crossword.puz")
+
+	puzzle.Board[0][1].Guess = puzzle.Board[0][1].Answer
+
+	revealed, err := puzzle.RevealCell(1, 0)
+	if err != nil {
+		t.Fatalf("Failed to reveal cell: %v", err)
+	}
+
+	if len(revealed) != 0 {
+		t.Fatalf("Expected a correct cell to not be revealed")
+	}
+
+	revealed, err = puzzle.RevealCell(0, 0)
+	if err != nil {
+		t.Fatalf("Failed to reveal cell: %v", err)
+	}
+
+	cell := puzzle.Board[0][0]
+	if len(revealed) != 1 || cell.Guess != cell.Answer || cell.Markup != puz.ContentGiven {
+		t.Fatalf("Expected the cell to be revealed and marked as given")
+	}
+
+	puzzle.Board[1][0].Guess = 'Q'
+	if puzzle.Board[1][0].Answer == 'Q' {
+		puzzle.Board[1][0].Guess = 'Z'
+	}
+
+	puzzle.CheckCell(0, 1)
+	puzzle.RevealCell(0, 1)
+
+	if puzzle.Board[1][0].Markup != puz.ContentGiven|puz.PreviouslyIncorrect {
+		t.Fatalf("Expected a revealed incorrect cell to be marked given and previously incorrect, found %x", puzzle.Board[1][0].Markup)
+	}
+}
+
+func TestRevealPuzzleWithRebus(t *testing.T) {
+	puzzle := loadUnsolved(t, "NYT-Nov2193.puz")
+
+	revealed, err := puzzle.RevealPuzzle()
+	if err != nil {
+		t.Fatalf("Failed to reveal puzzle: %v", err)
+	}
+
+	for _, pos := range revealed {
+		if puzzle.Guess(pos.X, pos.Y) != puzzle.Answer(pos.X, pos.Y) {
+			t.Fatalf("Cell x: %d y: %d was not fully revealed", pos.X, pos.Y)
+		}
+	}
+
+	if incorrect, _ := puzzle.CheckPuzzle(); len(incorrect) != 0 {
+		t.Fatalf("Expected a revealed puzzle to be correct, found %v", incorrect)
+	}
+
+	encoded, err := puz.EncodePuz(puzzle)
+	if err != nil {
+		t.Fatalf("Failed to encode revealed puzzle: %v", err)
+	}
+
+	decoded, err := puz.DecodePuz(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode revealed puzzle: %v", err)
+	}
+
+	if incorrect, _ := decoded.CheckPuzzle(); len(incorrect) != 0 {
+		t.Fatalf("Expected a decoded revealed puzzle to be correct, found %v", incorrect)
+	}
+}
+
+func TestRevealPartialRebus(t *testing.T) {
+	puzzle := loadUnsolved(t, "NYT-Nov2193.puz")
+
+	var rebus puz.Position
+	for _, word := range puzzle.Board.GetWords() {
+		if _, ok := puzzle.Rebus(word.StartX, word.StartY); ok {
+			rebus = puz.Position{X: word.StartX, Y: word.StartY}
+			break
+		}
+	}
+
+	cell := &puzzle.Board[rebus.Y][rebus.X]
+	cell.Guess = cell.Answer
+
+	revealed, err := puzzle.RevealCell(rebus.X, rebus.Y)
+	if err != nil {
+		t.Fatalf("Failed to reveal cell: %v", err)
+	}
+
+	if len(revealed) != 1 || puzzle.Guess(rebus.X, rebus.Y) != puzzle.Answer(rebus.X, rebus.Y) {
+		t.Fatalf("Expected the full rebus to be revealed, found %q", puzzle.Guess(rebus.X, rebus.Y))
+	}
+
+	if cell.Markup != puz.ContentGiven {
+		t.Fatalf("Expected a first letter guess to not be marked incorrect, found %x", cell.Markup)
+	}
+}
+
+func TestCheckScrambled(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "NYT-Locked.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode NYT-Locked.puz: %v", err)
+	}
+
+	if _, err := puzzle.CheckPuzzle(); err != puz.PuzzleIsScrambledError {
+		t.Fatalf("Expected PuzzleIsScrambledError, found %v", err)
+	}
+
+	if _, err := puzzle.RevealPuzzle(); err != puz.PuzzleIsScrambledError {
+		t.Fatalf("Expected PuzzleIsScrambledError, found %v", err)
+	}
+}
@@ -1,5 +1,10 @@
 package puz
 
+import (
+	"encoding/binary"
+	"fmt"
+)
+
 type checksums struct {
 	checksum           uint16
 	cibChecksum        uint16
@@ -82,3 +87,84 @@ func checksumRegion(buffer []byte, checksum uint16) uint16 {
 
 	return checksum
 }
+
+// ChecksumMismatch describes a checksum stored in a puz file that does not match the data it covers
+type ChecksumMismatch struct {
+	Checksum   Checksum
+	Section    string // name of the extra section, only set for ExtraSectionChecksum
+	Expected   int    // value stored in the file
+	Calculated int    // value computed from the data
+	offset     int    // position of the stored checksum in the file
+}
+
+func (m ChecksumMismatch) String() string {
+	if m.Checksum == ExtraSectionChecksum {
+		return fmt.Sprintf("%s section checksum: expected %d, calculated %d", m.Section, m.Expected, m.Calculated)
+	}
+
+	return fmt.Sprintf("%s: expected %d, calculated %d", m.Checksum.String(), m.Expected, m.Calculated)
+}
+
+// err converts the mismatch into the error returned when decoding strictly
+func (m ChecksumMismatch) err() error {
+	if m.Checksum == ExtraSectionChecksum {
+		return &ExtraSectionChecksumMismatchError{
+			uint16(m.Expected),
+			uint16(m.Calculated),
+			m.Section,
+		}
+	}
+
+	return &ChecksumMismatchError{
+		m.Expected,
+		m.Calculated,
+		m.Checksum,
+	}
+}
+
+// compareChecksums lists every header checksum that differs, headerOffset is the position of the header in the file
+func compareChecksums(found, computed *checksums, headerOffset int) []ChecksumMismatch {
+	var mismatches []ChecksumMismatch
+
+	if found.cibChecksum != computed.cibChecksum {
+		mismatches = append(mismatches, ChecksumMismatch{
+			CIBChecksum,
+			"",
+			int(found.cibChecksum),
+			int(computed.cibChecksum),
+			headerOffset + 14,
+		})
+	}
+
+	if found.checksum != computed.checksum {
+		mismatches = append(mismatches, ChecksumMismatch{
+			GlobalChecksum,
+			"",
+			int(found.checksum),
+			int(computed.checksum),
+			headerOffset,
+		})
+	}
+
+	if found.maskedLowChecksum != computed.maskedLowChecksum {
+		mismatches = append(mismatches, ChecksumMismatch{
+			MaskedLowChecksum,
+			"",
+			int(binary.LittleEndian.Uint32(found.maskedLowChecksum[:])),
+			int(binary.LittleEndian.Uint32(computed.maskedLowChecksum[:])),
+			headerOffset + 16,
+		})
+	}
+
+	if found.maskedHighChecksum != computed.maskedHighChecksum {
+		mismatches = append(mismatches, ChecksumMismatch{
+			MaskedHighChecksum,
+			"",
+			int(binary.LittleEndian.Uint32(found.maskedHighChecksum[:])),
+			int(binary.LittleEndian.Uint32(computed.maskedHighChecksum[:])),
+			headerOffset + 20,
+		})
+	}
+
+	return mismatches
+}
@@ -0,0 +1,196 @@
+package puz
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ConversionWarning describes data that could not be carried over when converting a puzzle to or from another format.
+type ConversionWarning struct {
+	Feature string // The feature that was dropped or changed, e.g. "styles"
+	Message string // A description of what happened to the data
+}
+
+func (w ConversionWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Feature, w.Message)
+}
+
+// symbols used for rebus cells in text formats, in order of preference
+var rebusSymbols = []byte("1234567890@$%&*+=?!~^")
+
+// rebusSymbolTable assigns grid symbols to rebus values for text formats
+type rebusSymbolTable struct {
+	reserved []byte          // Symbols that are written to the grid as answers
+	symbols  map[string]byte // The symbol assigned to each rebus value
+}
+
+// newRebusSymbolTable returns a rebusSymbolTable that will not assign any symbol used as an answer in board.
+func newRebusSymbolTable(board Board) *rebusSymbolTable {
+	var reserved []byte
+
+	for y := range board.Height() {
+		for x := range board.Width() {
+			if board[y][x].RebusKey == 0 {
+				reserved = append(reserved, board[y][x].Answer)
+			}
+		}
+	}
+
+	return &rebusSymbolTable{reserved, map[string]byte{}}
+}
+
+// symbol returns the symbol for value, assigning the next free symbol if value has not been seen.
+// isNew is true when the symbol was assigned by this call. Returns TooManyTextRebusValuesError if no symbols are left.
+func (t *rebusSymbolTable) symbol(value string) (byte, bool, error) {
+	if symbol, ok := t.symbols[value]; ok {
+		return symbol, false, nil
+	}
+
+	for _, symbol := range rebusSymbols {
+		if slices.Contains(t.reserved, symbol) {
+			continue
+		}
+
+		t.reserved = append(t.reserved, symbol)
+		t.symbols[value] = symbol
+		return symbol, true, nil
+	}
+
+	return 0, false, TooManyTextRebusValuesError
+}
+
+// conversionWarnings collects warnings during a conversion, ignoring repeats
+type conversionWarnings []ConversionWarning
+
+func (w *conversionWarnings) add(feature string, message string) {
+	warning := ConversionWarning{feature, message}
+
+	if !slices.Contains(*w, warning) {
+		*w = append(*w, warning)
+	}
+}
+
+// usesUTF8 reports if the strings in the puzzle are stored as UTF-8 instead of ISO-8859-1.
+// Only version 2.0 and later puz files allow non ASCII characters to be encoded as UTF-8.
+func (p *Puzzle) usesUTF8() bool {
+	return p.version[:3] >= "2.0"
+}
+
+// textToUTF8 converts a string stored in the puzzle into UTF-8.
+func (p *Puzzle) textToUTF8(s string) string {
+	if p.usesUTF8() {
+		return s
+	}
+
+	return latin1ToUTF8(s)
+}
+
+// textFromUTF8 converts a UTF-8 string into the encoding used by the puzzle.
+// ok is false if a character had to be replaced because the puzzle encoding can not represent it.
+func (p *Puzzle) textFromUTF8(s string) (string, bool) {
+	if p.usesUTF8() {
+		return s, true
+	}
+
+	return utf8ToLatin1(s)
+}
+
+// setTextVersion moves the puzzle to version 2.0 when any of texts can not be stored as ISO-8859-1.
+// Should be called on new puzzles before any imported text is converted.
+func (p *Puzzle) setTextVersion(texts []string) {
+	needsUTF8 := slices.ContainsFunc(texts, func(text string) bool {
+		_, ok := utf8ToLatin1(text)
+		return !ok
+	})
+
+	if needsUTF8 {
+		p.SetVersion("2.0")
+	}
+}
+
+// importText converts UTF-8 text from another format into the encoding used by the puzzle, reporting any replaced characters.
+func importText(p *Puzzle, value string, field string, warnings *conversionWarnings) string {
+	text, ok := p.textFromUTF8(value)
+	if !ok {
+		warnings.add("characters", fmt.Sprintf("the %s contains characters that can not be stored and were replaced", field))
+	}
+
+	return text
+}
+
+// exportCellText returns an answer or guess as UTF-8 text for another format, expanding rebus cells from table.
+// Returns an empty string if value is emptyValue.
+func exportCellText(p *Puzzle, value byte, rebusKey byte, table []RebusEntry, emptyValue byte) string {
+	if value == emptyValue {
+		return ""
+	}
+
+	if rebusKey != 0 {
+		entry, ok := rebusEntryByKey(table, int(rebusKey))
+		if ok {
+			return p.textToUTF8(entry.Value)
+		}
+	}
+
+	return p.textToUTF8(string([]byte{value}))
+}
+
+// latin1ToUTF8 converts ISO-8859-1 text into UTF-8.
+func latin1ToUTF8(s string) string {
+	var out strings.Builder
+
+	for i := range len(s) {
+		out.WriteRune(rune(s[i]))
+	}
+
+	return out.String()
+}
+
+// windows1252ToUTF8 converts Windows-1252 text into UTF-8.
+// Bytes 0x80 to 0x9F are mapped through winAnsiExtras, the bytes it does not define are kept like ISO-8859-1.
+func windows1252ToUTF8(s string) string {
+	runes := make(map[byte]rune, len(winAnsiExtras))
+	for r, b := range winAnsiExtras {
+		runes[b] = r
+	}
+
+	var out strings.Builder
+
+	for i := range len(s) {
+		if r, ok := runes[s[i]]; ok {
+			out.WriteRune(r)
+		} else {
+			out.WriteRune(rune(s[i]))
+		}
+	}
+
+	return out.String()
+}
+
+// winAnsiExtras maps characters outside of Latin-1 to their Windows-1252 bytes, the WinAnsiEncoding used by PDF fonts
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// utf8ToLatin1 converts UTF-8 text into ISO-8859-1.
+// Characters outside of ISO-8859-1 are replaced with '?' and ok is false.
+func utf8ToLatin1(s string) (string, bool) {
+	var out strings.Builder
+	ok := true
+
+	for _, r := range s {
+		if r > 0xFF {
+			out.WriteByte('?')
+			ok = false
+			continue
+		}
+
+		out.WriteByte(byte(r))
+	}
+
+	return out.String(), ok
+}
@@ -12,43 +12,62 @@ import (
 
 const headerSize = 52 // Header size in bytes
 
+// DecodeOptions controls how DecodePuzWithOptions handles problems in the data
+type DecodeOptions struct {
+	// Lenient loads puzzles with checksum mismatches instead of failing,
+	// every mismatch is returned alongside the puzzle
+	Lenient bool
+}
+
 // DecodePuz parses .puz file data from bytes and returns Puzzle
 func DecodePuz(data []byte) (*Puzzle, error) {
+	puzzle, _, err := DecodePuzWithOptions(data, DecodeOptions{})
+	return puzzle, err
+}
+
+// DecodePuzWithOptions parses .puz file data from bytes using the given options.
+// In lenient mode every checksum mismatch is returned, otherwise the first mismatch is returned as an error.
+func DecodePuzWithOptions(data []byte, opts DecodeOptions) (*Puzzle, []ChecksumMismatch, error) {
 	var puzzle Puzzle
+	var mismatches []ChecksumMismatch
 
 	reader := newPuzzleReader(data)
 
 	fileMagicIndex := reader.index([]byte(fileMagic))
 	if fileMagicIndex == -1 {
-		return nil, MissingFileMagicError
+		return nil, nil, MissingFileMagicError
 	}
 	preamble, err := reader.read(fileMagicIndex - 2)
 	puzzle.UnusedData.Preamble = preamble
 
 	foundChecksums, err := parseHeader(&reader, &puzzle)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse header: %w", err)
+		return nil, nil, fmt.Errorf("Failed to parse header: %w", err)
 	}
 
 	err = parseSolutionAndState(&reader, &puzzle)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse solution and state: %w", err)
+		return nil, nil, fmt.Errorf("Failed to parse solution and state: %w", err)
 	}
 
 	err = parseStringsSection(&reader, &puzzle)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to parse strings section: %w", err)
+		return nil, nil, fmt.Errorf("Failed to parse strings section: %w", err)
 	}
 
-	for range 5 {
-		err = parseExtraSection(&reader, &puzzle)
+	for {
+		mismatch, err := parseExtraSection(&reader, &puzzle, opts.Lenient)
 		if errors.Is(err, UnkownExtraSectionNameError) {
 			break
 		}
 
 		// when an extra section is found, but cant be read
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+
+		if mismatch != nil {
+			mismatches = append(mismatches, *mismatch)
 		}
 	}
 
@@ -58,12 +77,14 @@ func DecodePuz(data []byte) (*Puzzle, error) {
 	// to ensure only the actual data is checksummed
 	computedChecksums := computeChecksums(data[len(preamble):len(reader.data)-len(postscript)], puzzle.Board.Width()*puzzle.Board.Height(), puzzle.Title, puzzle.Author, puzzle.Copyright, puzzle.clues, puzzle.Notes, puzzle.version)
 
-	err = validateChecksums(foundChecksums, computedChecksums)
-	if err != nil {
-		return nil, err
+	headerMismatches := compareChecksums(foundChecksums, computedChecksums, len(preamble))
+	if !opts.Lenient && len(headerMismatches) > 0 {
+		return nil, nil, headerMismatches[0].err()
 	}
 
-	return &puzzle, nil
+	mismatches = append(headerMismatches, mismatches...)
+
+	return &puzzle, mismatches, nil
 }
 
 type puzzleReader struct {
@@ -109,7 +130,10 @@ func (r *puzzleReader) readStr() string {
 		r.offset++
 	}
 
-	r.offset++
+	// skip the null terminator if the data didn't end first
+	if r.offset < len(r.data) {
+		r.offset++
+	}
 
 	return string(data)
 }
@@ -141,6 +165,10 @@ func (r *puzzleReader) readShort() (uint16, error) {
 }
 
 func (r *puzzleReader) readRemaining() []byte {
+	if r.offset >= len(r.data) {
+		return r.data[len(r.data):]
+	}
+
 	return r.data[r.offset:len(r.data)]
 }
 
@@ -361,50 +389,78 @@ func parseStringsSection(reader *puzzleReader, puzzle *Puzzle) error {
 	return nil
 }
 
-func parseExtraSection(reader *puzzleReader, puzzle *Puzzle) error {
-	sectionName, err := reader.peek(4)
+// parseExtraSection reads the next extra section, in lenient mode a checksum mismatch is returned instead of an error
+func parseExtraSection(reader *puzzleReader, puzzle *Puzzle, lenient bool) (*ChecksumMismatch, error) {
+	header, err := reader.peek(8)
 	if err != nil {
-		return UnkownExtraSectionNameError
+		return nil, UnkownExtraSectionNameError
 	}
 
-	section, ok := getSectionFromString(string(sectionName))
-	if !ok {
-		return UnkownExtraSectionNameError
+	name := string(header[:4])
+
+	section, known := getSectionFromString(name)
+	if !known && !looksLikeExtraSection(reader) {
+		return nil, UnkownExtraSectionNameError
 	}
 
+	checksumOffset := reader.offset + 6
+
 	// because of peek, if we here there is data so no err check needed, just for shifting offset on valid section name
 	reader.read(4)
 
 	// length does not include null terminator
 	length, err := reader.readShort()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	checksum, err := reader.readShort()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	data, err := reader.read(int(length))
+	if err != nil {
+		return nil, err
+	}
 
 	computedChecksum := checksumRegion(data, 0x00)
 
+	var mismatch *ChecksumMismatch
+
 	if checksum != computedChecksum {
-		return &ExtraSectionChecksumMismatchError{
-			checksum,
-			computedChecksum,
-			section,
+		mismatch = &ChecksumMismatch{
+			ExtraSectionChecksum,
+			name,
+			int(checksum),
+			int(computedChecksum),
+			checksumOffset,
+		}
+
+		if !lenient {
+			return nil, mismatch.err()
 		}
 	}
 
-	if slices.Contains(puzzle.Extras.extraSectionOrder, section) {
-		return &DuplicateExtraSectionError{
-			section,
+	if slices.Contains(puzzle.Extras.extraSectionOrder, name) {
+		return nil, &DuplicateExtraSectionError{
+			name,
 		}
 	}
 
-	puzzle.Extras.extraSectionOrder = append(puzzle.Extras.extraSectionOrder, section)
+	puzzle.Extras.extraSectionOrder = append(puzzle.Extras.extraSectionOrder, name)
+
+	if !known {
+		puzzle.Extras.rawSections = append(puzzle.Extras.rawSections, RawExtraSection{
+			name,
+			slices.Clone(data),
+		})
+
+		// skip null terminator at the end of a section
+		reader.readByte()
+
+		return mismatch, nil
+	}
 
 	switch section {
 	case RebusSection:
@@ -412,7 +468,7 @@ func parseExtraSection(reader *puzzleReader, puzzle *Puzzle) error {
 		width := puzzle.Board.Width()
 		board, err := parseExtraSectionBoard(data, width, height)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for y := range height {
@@ -423,13 +479,13 @@ func parseExtraSection(reader *puzzleReader, puzzle *Puzzle) error {
 	case RebusTableSection:
 		tbl, err := parseExtraSectionRebusTbl(data)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		puzzle.Extras.RebusTable = tbl
 	case TimerSection:
 		timer, err := parseExtraTimerSection(data)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		puzzle.Extras.Timer = *timer
 	case MarkupBoardSection:
@@ -437,29 +493,42 @@ func parseExtraSection(reader *puzzleReader, puzzle *Puzzle) error {
 		width := puzzle.Board.Width()
 		board, err := parseExtraSectionBoard(data, width, height)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for y := range height {
 			for x := range width {
-				puzzle.Board[y][x].Markup = board[y][x]
+				puzzle.Board[y][x].Markup = MarkupSquare(board[y][x])
 			}
 		}
 	case UserRebusTableSection:
 		tbl, err := parseExtraSectionRebusTbl(data)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		puzzle.Extras.UserRebusTable = tbl
 
 	default:
-		return UnkownExtraSectionNameError
+		return nil, UnkownExtraSectionNameError
 	}
 
 	// skip null terminator at the end of a section
 	reader.readByte()
 
-	return nil
+	return mismatch, nil
+}
+
+// looksLikeExtraSection checks if the data at the reader offset has the shape of an extra section,
+// a valid name and a length that ends on a null terminator inside the data
+func looksLikeExtraSection(reader *puzzleReader) bool {
+	header, err := reader.peek(8)
+	if err != nil || !validSectionName(header[:4]) {
+		return false
+	}
+
+	end := reader.offset + 8 + int(parseShort(header[4:6]))
+
+	return end < reader.len() && reader.data[end] == 0x00
 }
 
 func parseExtraSectionBoard(data []byte, width int, height int) ([][]byte, error) {
@@ -478,6 +547,11 @@ func parseExtraSectionBoard(data []byte, width int, height int) ([][]byte, error
 }
 
 func parseExtraSectionRebusTbl(data []byte) ([]RebusEntry, error) {
+	// a table with no entries
+	if len(data) == 0 {
+		return []RebusEntry{}, nil
+	}
+
 	// last byte is a ; and should be ignored for proper splitting
 	str := string(data[:len(data)-1])
 
@@ -537,39 +611,3 @@ func parseExtraTimerSection(data []byte) (*TimerData, error) {
 		running,
 	}, nil
 }
-
-func validateChecksums(found, computed *checksums) error {
-	if found.cibChecksum != computed.cibChecksum {
-		return &ChecksumMismatchError{
-			int(found.cibChecksum),
-			int(computed.cibChecksum),
-			cibChecksum,
-		}
-	}
-
-	if found.checksum != computed.checksum {
-		return &ChecksumMismatchError{
-			int(found.checksum),
-			int(computed.checksum),
-			globalChecksum,
-		}
-	}
-
-	if found.maskedLowChecksum != computed.maskedLowChecksum {
-		return &ChecksumMismatchError{
-			int(binary.LittleEndian.Uint32(found.maskedLowChecksum[:])),
-			int(binary.LittleEndian.Uint32(computed.maskedLowChecksum[:])),
-			maskedLowChecksum,
-		}
-	}
-
-	if found.maskedHighChecksum != computed.maskedHighChecksum {
-		return &ChecksumMismatchError{
-			int(binary.LittleEndian.Uint32(found.maskedHighChecksum[:])),
-			int(binary.LittleEndian.Uint32(computed.maskedHighChecksum[:])),
-			maskedHighChecksum,
-		}
-	}
-
-	return nil
-}
@@ -4,10 +4,22 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"io"
+	"slices"
 )
 
 // EncodePuz encodes the data in puzzle to bytes that can be saved as a .puz file
 func EncodePuz(puzzle *Puzzle) ([]byte, error) {
+	writer, err := encodePuz(puzzle)
+	if err != nil {
+		return nil, err
+	}
+
+	return writer.bytes(), nil
+}
+
+// encodePuz writes the puzzle to a puzzleWriter and fills in the checksums.
+func encodePuz(puzzle *Puzzle) (*puzzleWriter, error) {
 	writer := newPuzzleWriter()
 
 	writer.writeBytes(puzzle.UnusedData.Preamble)
@@ -55,7 +67,7 @@ func EncodePuz(puzzle *Puzzle) ([]byte, error) {
 		return nil, err
 	}
 
-	return writer.bytes(), nil
+	return writer, nil
 }
 
 type puzzleWriter struct {
@@ -103,6 +115,10 @@ func (w *puzzleWriter) bytes() []byte {
 	return w.buffer.Bytes()
 }
 
+func (w *puzzleWriter) writeTo(out io.Writer) (int64, error) {
+	return w.buffer.WriteTo(out)
+}
+
 func (w *puzzleWriter) overwrite(offset int, newBytes []byte) error {
 	data := w.buffer.Bytes()
 
@@ -188,75 +204,103 @@ func encodeStringsSection(puzzle *Puzzle, writer *puzzleWriter) error {
 }
 
 func encodeExtraSections(puzzle *Puzzle, writer *puzzleWriter) error {
-	for _, section := range puzzle.Extras.extraSectionOrder {
-		var data []byte
+	order := puzzle.Extras.extraSectionOrder
 
-		switch section {
-		case RebusSection, MarkupBoardSection:
-			height := puzzle.Board.Height()
-			width := puzzle.Board.Width()
-			size := height * width
+	// markup can be set on cells without adding the section
+	if puzzle.Board.HasMarkup() && !puzzle.HasExtraSection(MarkupBoardSection) {
+		order = append(slices.Clone(order), MarkupBoardSection.String())
+	}
 
-			board := make([]byte, size)
+	for _, name := range order {
+		data, err := extraSectionData(puzzle, name)
+		if err != nil {
+			return err
+		}
 
-			for y := range height {
-				for x := range width {
-					var val byte
+		sectionLength := uint16(len(data))
+		checksum := checksumRegion(data, 0x00)
 
-					if section == RebusSection {
-						val = puzzle.Board[y][x].RebusKey
-					} else {
-						val = puzzle.Board[y][x].Markup
-					}
+		writer.writeBytes([]byte(name))
+		writer.writeShort(sectionLength)
+		writer.writeShort(checksum)
+		writer.writeBytes(data)
+		writer.writeByte(0x00)
+	}
 
-					board[(y*width)+x] = val
-				}
-			}
+	return nil
+}
 
-			data = board
-		case RebusTableSection:
-			if puzzle.Extras.RebusTable == nil {
-				return MissingExtraSectionError
-			}
+// extraSectionData builds the data for the named section, raw sections are returned as is
+func extraSectionData(puzzle *Puzzle, name string) ([]byte, error) {
+	section, known := getSectionFromString(name)
+	if !known {
+		raw, ok := puzzle.GetRawExtraSection(name)
+		if !ok {
+			return nil, MissingExtraSectionError
+		}
+
+		return raw.Data, nil
+	}
+
+	var data []byte
+
+	switch section {
+	case RebusSection, MarkupBoardSection:
+		height := puzzle.Board.Height()
+		width := puzzle.Board.Width()
+		size := height * width
+
+		board := make([]byte, size)
 
-			for _, entry := range puzzle.Extras.RebusTable {
-				padding := ""
-				if entry.Key-1 < 10 {
-					padding = " "
+		for y := range height {
+			for x := range width {
+				var val byte
+
+				if section == RebusSection {
+					val = puzzle.Board[y][x].RebusKey
+				} else {
+					val = byte(puzzle.Board[y][x].Markup)
 				}
-				data = fmt.Appendf(data, "%s%d:%s;", padding, entry.Key-1, entry.Value)
-			}
-		case TimerSection:
-			runningRep := 0
 
-			if !puzzle.Extras.Timer.Running {
-				runningRep = 1
+				board[(y*width)+x] = val
 			}
+		}
 
-			data = fmt.Appendf(data, "%d,%d", puzzle.Extras.Timer.SecondsPassed, runningRep)
-		case UserRebusTableSection:
-			if puzzle.Extras.UserRebusTable == nil {
-				return MissingExtraSectionError
-			}
+		data = board
+	case RebusTableSection:
+		if puzzle.Extras.RebusTable == nil {
+			return nil, MissingExtraSectionError
+		}
 
-			for _, entry := range puzzle.Extras.UserRebusTable {
-				padding := ""
-				if entry.Key-1 < 10 {
-					padding = " "
-				}
-				data = fmt.Appendf(data, "%s%d:%s;", padding, entry.Key-1, entry.Value)
+		// entries that are not used by any cell are dropped
+		for _, entry := range usedRebusEntries(puzzle.Board, puzzle.Extras.RebusTable) {
+			padding := ""
+			if entry.Key-1 < 10 {
+				padding = " "
 			}
+			data = fmt.Appendf(data, "%s%d:%s;", padding, entry.Key-1, entry.Value)
 		}
+	case TimerSection:
+		runningRep := 0
 
-		sectionLength := uint16(len(data))
-		checksum := checksumRegion(data, 0x00)
+		if !puzzle.Extras.Timer.Running {
+			runningRep = 1
+		}
 
-		writer.writeBytes([]byte(section.String()))
-		writer.writeShort(sectionLength)
-		writer.writeShort(checksum)
-		writer.writeBytes(data)
-		writer.writeByte(0x00)
+		data = fmt.Appendf(data, "%d,%d", puzzle.Extras.Timer.SecondsPassed, runningRep)
+	case UserRebusTableSection:
+		if puzzle.Extras.UserRebusTable == nil {
+			return nil, MissingExtraSectionError
+		}
+
+		for _, entry := range usedRebusEntries(puzzle.Board, puzzle.Extras.UserRebusTable) {
+			padding := ""
+			if entry.Key-1 < 10 {
+				padding = " "
+			}
+			data = fmt.Appendf(data, "%s%d:%s;", padding, entry.Key-1, entry.Value)
+		}
 	}
 
-	return nil
+	return data, nil
 }
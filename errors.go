@@ -3,6 +3,7 @@ package puz
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 var (
@@ -18,37 +19,72 @@ var (
 	InvalidVersionFormatError          = errors.New("Invalid version format, must be X.X")
 	TooFewCharactersToUnscrambleError  = errors.New("Too few characters to unscramble, minimum 12")
 	TooFewCharactersToScrambleError    = errors.New("Too few characters to scramble, minimum 12")
-	NonLetterCharactersInScrambleError = errors.New("Scramble operations can not be performed on grids with non-letter characters")
+	NonLetterCharactersInScrambleError = errors.New("Scramble operations can not be performed on grids with lowercase, blank, or non-ASCII answers")
 	InvalidDigitInKeyError             = errors.New("Key cannot contain any zeros")
 	InvalidKeyLengthError              = errors.New("Key must be a 4-digit number")
 	IncorrectKeyProvidedError          = errors.New("Failed to unscramble, incorrect key provided")
+	RebusTableFullError                = errors.New("Rebus table has no free keys")
+	EmptyRebusValueError               = errors.New("Rebus value cannot be empty")
+	NotARebusCellError                 = errors.New("Cell does not contain a rebus")
+	UnsupportedIpuzKindError           = errors.New("ipuz file is not a crossword")
+	InvalidIpuzDimensionsError         = errors.New("ipuz dimensions do not match the puzzle grid")
+	InvalidJpzGridError                = errors.New("jpz grid size is invalid or a cell is outside of the grid")
+	MissingJpzPuzzleError              = errors.New("Failed to find a rectangular-puzzle in jpz data")
+	UnsupportedJpzCharsetError         = errors.New("Unsupported jpz charset, must be UTF-8, ISO-8859-1, or Windows-1252")
+	MissingTextHeaderError             = errors.New("Failed to find <ACROSS PUZZLE> header")
+	UnknownTextSectionError            = errors.New("Unknown text section name")
+	DuplicateTextSectionError          = errors.New("A duplicate text section was found")
+	MissingTextSectionError            = errors.New("A required text section was not found")
+	TextSectionRequiresV2Error         = errors.New("Section is only allowed in <ACROSS PUZZLE V2> files")
+	UnexpectedTextLineError            = errors.New("Found text outside of a section")
+	InvalidTextSizeError               = errors.New("Invalid size, must be WIDTHxHEIGHT")
+	EmptyTextGridError                 = errors.New("Grid section is empty")
+	TextGridSizeMismatchError          = errors.New("Grid does not match the size section")
+	LowercaseTextGridError             = errors.New("Lowercase letters in the grid require MARK; in the rebus section")
+	InvalidTextRebusError              = errors.New("Invalid rebus, must be SYMBOL:ANSWER:SHORT")
+	TooManyTextRebusValuesError        = errors.New("Too many distinct rebus values to assign symbols")
+	DataTooLargeError                  = errors.New("Data is larger than the max decode size")
+	InvalidXdHeaderError               = errors.New("Invalid header, must be Key: Value")
+	InvalidXdRebusError                = errors.New("Invalid rebus header, must be SYMBOL=ANSWER pairs")
+	DuplicateXdClueError               = errors.New("A duplicate clue was found")
+	InvalidExtraSectionNameError       = errors.New("Extra section names must be 4 uppercase letters or digits and not a known section")
+	ExtraSectionTooLargeError          = errors.New("Extra section data can not be longer than 65535 bytes")
+	SymmetryRequiresSquareBoardError   = errors.New("Quarter turn and diagonal symmetry require a square board")
+	NoFillFoundError                   = errors.New("No words from the word list fill the grid")
+	InvalidGenerateSizeError           = errors.New("Board must be at least the minimum word length in both directions")
+	InvalidBlackSquareRatioError       = errors.New("Black square ratio must be between 0 and 1")
+	WordLimitTooLowError               = errors.New("Board has more words than the word limit")
+	PDFContentTooLargeError            = errors.New("Puzzle does not fit on one page")
 )
 
-// Checksum Mismatch
-type checksum int
+// Checksum identifies one of the checksums stored in a puz file
+type Checksum int
 
 const (
-	globalChecksum checksum = iota
-	cibChecksum
-	maskedLowChecksum
-	maskedHighChecksum
+	GlobalChecksum Checksum = iota
+	CIBChecksum
+	MaskedLowChecksum
+	MaskedHighChecksum
+	ExtraSectionChecksum
 )
 
-var checksumStrMap = map[checksum]string{
-	globalChecksum:     "Global Checksum",
-	cibChecksum:        "CIB Checksum",
-	maskedLowChecksum:  "Masked Low Checksum",
-	maskedHighChecksum: "Masked High Checksum",
+var checksumStrMap = map[Checksum]string{
+	GlobalChecksum:       "Global Checksum",
+	CIBChecksum:          "CIB Checksum",
+	MaskedLowChecksum:    "Masked Low Checksum",
+	MaskedHighChecksum:   "Masked High Checksum",
+	ExtraSectionChecksum: "Extra Section Checksum",
 }
 
-func (c checksum) String() string {
+func (c Checksum) String() string {
 	return checksumStrMap[c]
 }
 
+// Checksum Mismatch
 type ChecksumMismatchError struct {
 	expected   int
 	calculated int
-	checksum   checksum
+	checksum   Checksum
 }
 
 func (e *ChecksumMismatchError) Error() string {
@@ -59,11 +95,35 @@ func (e *ChecksumMismatchError) Error() string {
 type ExtraSectionChecksumMismatchError struct {
 	expected   uint16
 	calculated uint16
-	section    ExtraSection
+	section    string
 }
 
 func (e *ExtraSectionChecksumMismatchError) Error() string {
-	return fmt.Sprintf("%s section checksum mismatch: expected %d, calculated %d", e.section.String(), e.expected, e.calculated)
+	return fmt.Sprintf("%s section checksum mismatch: expected %d, calculated %d", e.section, e.expected, e.calculated)
+}
+
+// Unscramblable Cells
+type UnscramblableCellsError struct {
+	cells []Position
+}
+
+func (e *UnscramblableCellsError) Error() string {
+	var cells []string
+
+	for _, cell := range e.cells {
+		cells = append(cells, fmt.Sprintf("(%d, %d)", cell.X, cell.Y))
+	}
+
+	return fmt.Sprintf("%v: %s", NonLetterCharactersInScrambleError, strings.Join(cells, ", "))
+}
+
+// Cells returns the positions of the answers that prevent scrambling, in reading order
+func (e *UnscramblableCellsError) Cells() []Position {
+	return e.cells
+}
+
+func (e *UnscramblableCellsError) Unwrap() error {
+	return NonLetterCharactersInScrambleError
 }
 
 // Clue Mismatch
@@ -78,9 +138,9 @@ func (e *ClueCountMismatchError) Error() string {
 
 // Duplicate Extra Section
 type DuplicateExtraSectionError struct {
-	section ExtraSection
+	section string
 }
 
 func (e *DuplicateExtraSectionError) Error() string {
-	return fmt.Sprintf("A duplicate %s section was found", e.section.String())
+	return fmt.Sprintf("A duplicate %s section was found", e.section)
 }
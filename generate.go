@@ -0,0 +1,142 @@
+package puz
+
+import (
+	"math/rand/v2"
+)
+
+// DefaultMaxBlackRatio is the share of solid squares GenerateBoard stops at when no limit is set, close to a typical 15x15 grid
+const DefaultMaxBlackRatio = 1.0 / 6
+
+// GenerateOptions controls the board built by GenerateBoard
+type GenerateOptions struct {
+	Width         uint8    // The number of columns
+	Height        uint8    // The number of rows
+	Symmetry      Symmetry // Every solid square is placed with its symmetric squares, use RotationalSymmetry for American style grids
+	MinWordLength int      // The shortest allowed word, defaults to 3
+	MaxWords      int      // The most words the board can have, 0 for no limit
+	MaxBlackRatio float64  // The largest share of squares that can be solid, defaults to DefaultMaxBlackRatio when MaxWords is also 0
+	Seed          uint64   // Boards generated with the same options and seed are identical
+}
+
+// GenerateBoard returns a blank board of opts.Width x opts.Height with solid squares placed at random.
+//
+// Solid squares are added until no more can be placed without breaking a limit.
+// The board keeps the requested symmetry, has no words shorter than opts.MinWordLength, and every white square is connected.
+// White squares have an EmptySolutionSquare answer and an EmptyStateSquare guess.
+// Returns InvalidGenerateSizeError if the board is smaller than the minimum word length in either direction,
+// and WordLimitTooLowError if the board has more than opts.MaxWords words even without solid squares.
+func GenerateBoard(opts GenerateOptions) (Board, error) {
+	minLength := opts.MinWordLength
+	if minLength <= 0 {
+		minLength = 3
+	}
+
+	maxRatio := opts.MaxBlackRatio
+	if maxRatio == 0 && opts.MaxWords == 0 {
+		maxRatio = DefaultMaxBlackRatio
+	}
+
+	if maxRatio < 0 || maxRatio > 1 {
+		return nil, InvalidBlackSquareRatioError
+	}
+
+	if int(opts.Width) < minLength || int(opts.Height) < minLength {
+		return nil, InvalidGenerateSizeError
+	}
+
+	if opts.Symmetry.requiresSquare() && opts.Width != opts.Height {
+		return nil, SymmetryRequiresSquareBoardError
+	}
+
+	board := NewBoard(opts.Width, opts.Height)
+	total := board.Width() * board.Height()
+	maxSolid := total
+	if maxRatio > 0 {
+		maxSolid = int(maxRatio * float64(total))
+	}
+
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+	cells := board.cells()
+	rng.Shuffle(len(cells), func(i int, j int) {
+		cells[i], cells[j] = cells[j], cells[i]
+	})
+
+	solid := 0
+
+	for _, pos := range cells {
+		if board.IsSolidSquare(pos.X, pos.Y) {
+			continue
+		}
+
+		changed, err := board.SetSolidSymmetric(pos.X, pos.Y, opts.Symmetry, true)
+		if err != nil {
+			return nil, err
+		}
+
+		fits := solid+len(changed) <= maxSolid &&
+			board.wordsAtLeast(minLength) &&
+			(opts.MaxWords == 0 || len(board.GetWords()) <= opts.MaxWords) &&
+			len(board.whiteRegions()) <= 1
+
+		if !fits {
+			// the squares were all white, so clearing them restores the board
+			_, err := board.SetSolidSymmetric(pos.X, pos.Y, opts.Symmetry, false)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		solid += len(changed)
+	}
+
+	// every placement is checked against the limit, so this only fails when the open board is already over it
+	if opts.MaxWords > 0 && len(board.GetWords()) > opts.MaxWords {
+		return nil, WordLimitTooLowError
+	}
+
+	return board, nil
+}
+
+// wordsAtLeast reports if every run of white squares across and down, including single squares, has at least length squares.
+func (b Board) wordsAtLeast(length int) bool {
+	width := b.Width()
+	height := b.Height()
+
+	for y := range height {
+		run := 0
+
+		for x := range width + 1 {
+			if x < width && !b.IsSolidSquare(x, y) {
+				run++
+				continue
+			}
+
+			if run > 0 && run < length {
+				return false
+			}
+
+			run = 0
+		}
+	}
+
+	for x := range width {
+		run := 0
+
+		for y := range height + 1 {
+			if y < height && !b.IsSolidSquare(x, y) {
+				run++
+				continue
+			}
+
+			if run > 0 && run < length {
+				return false
+			}
+
+			run = 0
+		}
+	}
+
+	return true
+}
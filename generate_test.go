@@ -0,0 +1,107 @@
+package puz_test
+
+import (
+	"errors"
+	puz "github.com/cqb13/puz-parser"
+	"testing"
+)
+
+func countSolid(board puz.Board) int {
+	solid := 0
+
+	for y := range board.Height() {
+		for x := range board.Width() {
+			if board.IsSolidSquare(x, y) {
+				solid++
+			}
+		}
+	}
+
+	return solid
+}
+
+func TestGenerateBoard(t *testing.T) {
+	for seed := range uint64(10) {
+		opts := puz.GenerateOptions{Width: 15, Height: 15, Symmetry: puz.RotationalSymmetry, Seed: seed}
+
+		board, err := puz.GenerateBoard(opts)
+		if err != nil {
+			t.Fatalf("Failed to generate board: %v", err)
+		}
+
+		if board.Width() != 15 || board.Height() != 15 {
+			t.Fatalf("Expected a 15x15 board, found %dx%d", board.Width(), board.Height())
+		}
+
+		if !board.Symmetry().Has(puz.RotationalSymmetry) {
+			t.Fatalf("Expected rotational symmetry for seed %d, found %s", seed, board.Symmetry())
+		}
+
+		// a sixth of 225 squares
+		solid := countSolid(board)
+		if solid == 0 || solid > 37 {
+			t.Fatalf("Expected between 1 and 37 solid squares for seed %d, found %d", seed, solid)
+		}
+
+		issues := puz.NewPuzzleFromBoard(board).Validate()
+		for _, kind := range []puz.IssueKind{puz.ShortWordIssue, puz.UncheckedSquareIssue, puz.DisconnectedGridIssue} {
+			if count := countIssues(issues, kind); count != 0 {
+				t.Fatalf("Expected no %s issues for seed %d, found %d", kind, seed, count)
+			}
+		}
+	}
+}
+
+func TestGenerateBoardSeeded(t *testing.T) {
+	opts := puz.GenerateOptions{Width: 13, Height: 11, Symmetry: puz.RotationalSymmetry, Seed: 42}
+
+	first, err := puz.GenerateBoard(opts)
+	if err != nil {
+		t.Fatalf("Failed to generate board: %v", err)
+	}
+
+	second, err := puz.GenerateBoard(opts)
+	if err != nil {
+		t.Fatalf("Failed to generate board: %v", err)
+	}
+
+	for y := range first.Height() {
+		for x := range first.Width() {
+			if first.IsSolidSquare(x, y) != second.IsSolidSquare(x, y) {
+				t.Fatalf("Expected boards with the same seed to match at x: %d y: %d", x, y)
+			}
+		}
+	}
+}
+
+func TestGenerateBoardMaxWords(t *testing.T) {
+	opts := puz.GenerateOptions{Width: 15, Height: 15, Symmetry: puz.RotationalSymmetry, MaxWords: 60, Seed: 7}
+
+	board, err := puz.GenerateBoard(opts)
+	if err != nil {
+		t.Fatalf("Failed to generate board: %v", err)
+	}
+
+	if words := len(board.GetWords()); words > 60 || words <= 30 {
+		t.Fatalf("Expected between 31 and 60 words, found %d", words)
+	}
+}
+
+func TestGenerateBoardErrors(t *testing.T) {
+	testCases := []struct {
+		opts     puz.GenerateOptions
+		expected error
+	}{
+		{puz.GenerateOptions{Width: 2, Height: 15}, puz.InvalidGenerateSizeError},
+		{puz.GenerateOptions{Width: 15, Height: 15, MaxBlackRatio: 1.5}, puz.InvalidBlackSquareRatioError},
+		{puz.GenerateOptions{Width: 15, Height: 13, Symmetry: puz.QuarterTurnSymmetry}, puz.SymmetryRequiresSquareBoardError},
+		// an open 15x15 board already has 30 words
+		{puz.GenerateOptions{Width: 15, Height: 15, Symmetry: puz.RotationalSymmetry, MaxWords: 20}, puz.WordLimitTooLowError},
+	}
+
+	for _, tc := range testCases {
+		if _, err := puz.GenerateBoard(tc.opts); !errors.Is(err, tc.expected) {
+			t.Errorf("Expected %v, found %v", tc.expected, err)
+		}
+	}
+}
@@ -0,0 +1,648 @@
+package puz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const ipuzVersion string = "http://ipuz.org/v2"
+const ipuzCrosswordKind string = "http://ipuz.org/crossword#1"
+const ipuzDiagramlessKind string = "http://ipuz.org/crossword/diagramless#1"
+const ipuzDefaultBlock string = "#"
+const ipuzDefaultEmpty string = "0"
+
+// ipuz fields that have no equivalent in the puz format
+var ipuzUnsupportedFields = []string{
+	"annotation",
+	"answer",
+	"answers",
+	"checksum",
+	"clueplacement",
+	"date",
+	"difficulty",
+	"editor",
+	"enumeration",
+	"enumerations",
+	"explanation",
+	"fakeclues",
+	"intro",
+	"misses",
+	"origin",
+	"publication",
+	"publisher",
+	"showenumerations",
+	"uniqueid",
+	"url",
+	"volatile",
+}
+
+type ipuzFile struct {
+	Version    string                       `json:"version"`
+	Kind       []string                     `json:"kind"`
+	Title      string                       `json:"title"`
+	Author     string                       `json:"author"`
+	Copyright  string                       `json:"copyright"`
+	Notes      string                       `json:"notes"`
+	Dimensions ipuzDimensions               `json:"dimensions"`
+	Block      json.RawMessage              `json:"block"`
+	Empty      json.RawMessage              `json:"empty"`
+	Puzzle     [][]json.RawMessage          `json:"puzzle"`
+	Solution   [][]json.RawMessage          `json:"solution"`
+	Saved      [][]json.RawMessage          `json:"saved"`
+	Clues      map[string][]json.RawMessage `json:"clues"`
+	Styles     map[string]json.RawMessage   `json:"styles"`
+}
+
+type ipuzDimensions struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ipuzCell is a normalized cell from one of the ipuz grids
+type ipuzCell struct {
+	value   string                     // The cell label, answer, or guess depending on the grid
+	omitted bool                       // The cell was null, meaning it is not part of the puzzle
+	style   map[string]json.RawMessage // The style applied to the cell, if any
+}
+
+// DecodeIpuz parses an ipuz crossword from JSON and returns Puzzle.
+//
+// Answers, guesses, rebus cells, circled cells, clues, and the title, author, copyright, and notes are converted.
+// Any ipuz data that can not be stored in a puz file, such as styles, enumerations, and barred edges, is reported in the returned warnings.
+// Returns UnsupportedIpuzKindError if the file is not a crossword and InvalidIpuzDimensionsError if the grids do not match the dimensions.
+func DecodeIpuz(data []byte) (*Puzzle, []ConversionWarning, error) {
+	var file ipuzFile
+	var fields map[string]json.RawMessage
+	var warnings conversionWarnings
+
+	err := json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to parse ipuz json: %w", err)
+	}
+
+	err = json.Unmarshal(data, &file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to parse ipuz json: %w", err)
+	}
+
+	puzzleType, ok := ipuzPuzzleType(file.Kind)
+	if !ok {
+		return nil, nil, UnsupportedIpuzKindError
+	}
+
+	for _, field := range ipuzUnsupportedFields {
+		if _, ok := fields[field]; ok {
+			warnings.add(field, fmt.Sprintf("the %q field is not supported by the puz format and was dropped", field))
+		}
+	}
+
+	width := file.Dimensions.Width
+	height := file.Dimensions.Height
+	if width < 1 || width > 255 || height < 1 || height > 255 {
+		return nil, nil, InvalidIpuzDimensionsError
+	}
+
+	for _, grid := range [][][]json.RawMessage{file.Puzzle, file.Solution, file.Saved} {
+		if grid != nil && !ipuzGridFits(grid, width, height) {
+			return nil, nil, InvalidIpuzDimensionsError
+		}
+	}
+
+	if file.Puzzle == nil {
+		return nil, nil, InvalidIpuzDimensionsError
+	}
+
+	block := ipuzScalar(file.Block, ipuzDefaultBlock)
+	empty := ipuzScalar(file.Empty, ipuzDefaultEmpty)
+
+	found, err := ipuzClueLists(file.Clues, &warnings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	puzzle := NewPuzzle(uint8(width), uint8(height))
+	puzzle.PuzzleType = puzzleType
+
+	texts := []string{file.Title, file.Author, file.Copyright, file.Notes}
+	for _, list := range found {
+		for _, clue := range list {
+			texts = append(texts, clue.text)
+		}
+	}
+	puzzle.setTextVersion(texts)
+
+	labels := make([][]string, height)
+	answerMissing := false
+
+	for y := range height {
+		labels[y] = make([]string, width)
+
+		for x := range width {
+			cell, err := parseIpuzCell(file.Puzzle[y][x], file.Styles)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Failed to parse ipuz puzzle cell %d,%d: %w", x, y, err)
+			}
+
+			solid := cell.omitted || cell.value == block
+			if cell.omitted {
+				warnings.add("omitted cells", "cells outside of the puzzle were converted to solid squares")
+			}
+
+			var solution ipuzCell
+			if file.Solution != nil {
+				solution, err = parseIpuzCell(file.Solution[y][x], file.Styles)
+				if err != nil {
+					return nil, nil, fmt.Errorf("Failed to parse ipuz solution cell %d,%d: %w", x, y, err)
+				}
+
+				solid = solid || solution.value == block
+			}
+
+			if solid {
+				puzzle.Board[y][x].Answer = SolidSquare
+				puzzle.Board[y][x].Guess = SolidSquare
+				continue
+			}
+
+			if cell.value != empty {
+				labels[y][x] = cell.value
+			}
+
+			if ipuzApplyStyle(&puzzle.Board[y][x], cell.style, &warnings) {
+				puzzle.AddExtraSection(MarkupBoardSection)
+			}
+
+			answer, ok := utf8ToLatin1(strings.ToUpper(solution.value))
+			if !ok {
+				warnings.add("characters", fmt.Sprintf("answer at %d,%d contains characters that can not be stored", x, y))
+			}
+
+			switch {
+			case solution.omitted || answer == "" || answer == empty:
+				answerMissing = true
+			case len(answer) == 1:
+				puzzle.Board[y][x].Answer = answer[0]
+			default:
+				err = puzzle.SetRebus(x, y, answer)
+				if err != nil {
+					return nil, nil, err
+				}
+			}
+
+			if file.Saved == nil {
+				continue
+			}
+
+			saved, err := parseIpuzCell(file.Saved[y][x], file.Styles)
+			if err != nil {
+				return nil, nil, fmt.Errorf("Failed to parse ipuz saved cell %d,%d: %w", x, y, err)
+			}
+
+			guess, _ := utf8ToLatin1(strings.ToUpper(saved.value))
+			switch {
+			case saved.omitted || guess == "" || guess == empty:
+			case len(guess) == 1:
+				puzzle.Board[y][x].Guess = guess[0]
+			default:
+				err = puzzle.SetUserRebus(x, y, guess)
+				if err != nil {
+					puzzle.Board[y][x].Guess = guess[0]
+					warnings.add("saved", fmt.Sprintf("multi-letter guess at %d,%d is not in a rebus cell and was shortened to its first letter", x, y))
+				}
+			}
+		}
+	}
+
+	if answerMissing {
+		warnings.add("solution", "some cells have no answer and were left empty")
+	}
+
+	puzzle.Title = importText(puzzle, file.Title, "title", &warnings)
+	puzzle.Author = importText(puzzle, file.Author, "author", &warnings)
+	puzzle.Copyright = importText(puzzle, file.Copyright, "copyright", &warnings)
+	puzzle.Notes = importText(puzzle, file.Notes, "notes", &warnings)
+
+	puzzle.SetClues(ipuzClues(puzzle, found, labels, &warnings))
+
+	return puzzle, warnings, nil
+}
+
+// EncodeIpuz encodes the puzzle as an ipuz crossword.
+//
+// Data that ipuz can not hold, such as the timer and incorrect or given markup, is reported in the returned warnings.
+// Returns PuzzleIsScrambledError if the puzzle is scrambled because the answers can not be exported.
+func EncodeIpuz(puzzle *Puzzle) ([]byte, []ConversionWarning, error) {
+	var warnings conversionWarnings
+
+	if puzzle.Scrambled() {
+		return nil, nil, PuzzleIsScrambledError
+	}
+
+	width := puzzle.Board.Width()
+	height := puzzle.Board.Height()
+
+	kind := ipuzCrosswordKind
+	if puzzle.PuzzleType == Diagramless {
+		kind = ipuzDiagramlessKind
+	}
+
+	numbers := puzzle.Board.cellNumbers()
+
+	grid := make([][]any, height)
+	solution := make([][]any, height)
+	saved := make([][]any, height)
+	hasGuesses := false
+
+	for y := range height {
+		grid[y] = make([]any, width)
+		solution[y] = make([]any, width)
+		saved[y] = make([]any, width)
+
+		for x := range width {
+			cell := puzzle.Board[y][x]
+
+			if puzzle.Board.IsSolidSquare(x, y) {
+				grid[y][x] = ipuzDefaultBlock
+				solution[y][x] = ipuzDefaultBlock
+				saved[y][x] = ipuzDefaultBlock
+				continue
+			}
+
+			grid[y][x] = numbers[y][x]
+			if cell.IsCircled() {
+				grid[y][x] = map[string]any{
+					"cell":  numbers[y][x],
+					"style": map[string]string{"shapebg": "circle"},
+				}
+			}
+
+			if cell.Markup&^SquareCircled != 0 {
+				warnings.add("markup", "incorrect and given markup is not supported by ipuz and was dropped")
+			}
+
+			solution[y][x] = ipuzCellValue(exportCellText(puzzle, cell.Answer, cell.RebusKey, puzzle.Extras.RebusTable, EmptySolutionSquare))
+			saved[y][x] = ipuzCellValue(exportCellText(puzzle, cell.Guess, cell.RebusKey, puzzle.Extras.UserRebusTable, EmptyStateSquare))
+
+			if cell.Guess != EmptyStateSquare {
+				hasGuesses = true
+			}
+		}
+	}
+
+	if puzzle.HasExtraSection(TimerSection) {
+		warnings.add("timer", "the timer is not supported by ipuz and was dropped")
+	}
+
+	clues := map[string][][]any{}
+	for _, clue := range puzzle.clues {
+		dir := "Across"
+		if clue.Direction == Down {
+			dir = "Down"
+		}
+
+		clues[dir] = append(clues[dir], []any{clue.Num, puzzle.textToUTF8(clue.Clue)})
+	}
+
+	file := map[string]any{
+		"version":    ipuzVersion,
+		"kind":       []string{kind},
+		"dimensions": ipuzDimensions{width, height},
+		"block":      ipuzDefaultBlock,
+		"empty":      0,
+		"puzzle":     grid,
+		"solution":   solution,
+		"clues":      clues,
+	}
+
+	if hasGuesses {
+		file["saved"] = saved
+	}
+
+	fields := map[string]string{
+		"title":     puzzle.Title,
+		"author":    puzzle.Author,
+		"copyright": puzzle.Copyright,
+		"notes":     puzzle.Notes,
+	}
+
+	for name, value := range fields {
+		if value != "" {
+			file[name] = puzzle.textToUTF8(value)
+		}
+	}
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "\t")
+
+	err := encoder.Encode(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to encode ipuz json: %w", err)
+	}
+
+	return out.Bytes(), warnings, nil
+}
+
+// ipuzPuzzleType returns the puzzle type for the ipuz kinds, ok is false if none of the kinds are crosswords.
+func ipuzPuzzleType(kinds []string) (PuzzleType, bool) {
+	puzzleType := Normal
+	found := false
+
+	for _, kind := range kinds {
+		if strings.HasPrefix(kind, "http://ipuz.org/crossword/diagramless") {
+			puzzleType = Diagramless
+			found = true
+		} else if strings.HasPrefix(kind, "http://ipuz.org/crossword") {
+			found = true
+		}
+	}
+
+	return puzzleType, found
+}
+
+// ipuzGridFits reports if grid has exactly height rows of width cells.
+func ipuzGridFits(grid [][]json.RawMessage, width int, height int) bool {
+	if len(grid) != height {
+		return false
+	}
+
+	for _, row := range grid {
+		if len(row) != width {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ipuzScalar returns a json number or string as a string, or fallback if raw is empty.
+func ipuzScalar(raw json.RawMessage, fallback string) string {
+	if len(raw) == 0 {
+		return fallback
+	}
+
+	var str string
+	if json.Unmarshal(raw, &str) == nil {
+		return str
+	}
+
+	var num json.Number
+	if json.Unmarshal(raw, &num) == nil {
+		return num.String()
+	}
+
+	return fallback
+}
+
+// parseIpuzCell normalizes a cell from the puzzle, solution, or saved grids.
+//
+// A cell is either null, a number, a string, or an object with a "cell" or "value" along with an optional "style".
+func parseIpuzCell(raw json.RawMessage, styles map[string]json.RawMessage) (ipuzCell, error) {
+	raw = bytes.TrimSpace(raw)
+
+	if len(raw) == 0 || string(raw) == "null" {
+		return ipuzCell{"", true, nil}, nil
+	}
+
+	if raw[0] != '{' {
+		var num json.Number
+		err := json.Unmarshal(raw, &num)
+		if err == nil {
+			return ipuzCell{num.String(), false, nil}, nil
+		}
+
+		var str string
+		err = json.Unmarshal(raw, &str)
+		if err != nil {
+			return ipuzCell{}, err
+		}
+
+		return ipuzCell{str, false, nil}, nil
+	}
+
+	var obj struct {
+		Cell  json.RawMessage `json:"cell"`
+		Value json.RawMessage `json:"value"`
+		Style json.RawMessage `json:"style"`
+	}
+
+	err := json.Unmarshal(raw, &obj)
+	if err != nil {
+		return ipuzCell{}, err
+	}
+
+	cell := ipuzCell{"", false, nil}
+
+	if len(obj.Value) > 0 {
+		cell.value = ipuzScalar(obj.Value, "")
+	} else if len(obj.Cell) > 0 {
+		cell.value = ipuzScalar(obj.Cell, "")
+	}
+
+	if len(obj.Style) > 0 {
+		var name string
+		if json.Unmarshal(obj.Style, &name) == nil {
+			err = json.Unmarshal(styles[name], &cell.style)
+		} else {
+			err = json.Unmarshal(obj.Style, &cell.style)
+		}
+
+		if err != nil {
+			return ipuzCell{}, err
+		}
+	}
+
+	return cell, nil
+}
+
+// ipuzApplyStyle applies a circle style as SquareCircled markup and reports any other style properties.
+// Returns true if markup was added to the cell.
+func ipuzApplyStyle(cell *Cell, style map[string]json.RawMessage, warnings *conversionWarnings) bool {
+	circled := false
+
+	for _, key := range slices.Sorted(maps.Keys(style)) {
+		value := style[key]
+
+		switch key {
+		case "shapebg":
+			if ipuzScalar(value, "") == "circle" {
+				circled = true
+				continue
+			}
+			warnings.add("styles", "background shapes other than circles are not supported and were dropped")
+		case "barred":
+			warnings.add("barred edges", "barred cell edges are not supported and were dropped")
+		default:
+			warnings.add("styles", fmt.Sprintf("the %q style is not supported and was dropped", key))
+		}
+	}
+
+	if circled {
+		cell.SetCircled(true)
+	}
+
+	return circled
+}
+
+// ipuzCellValue returns the ipuz value for an answer or guess, 0 for an empty cell.
+func ipuzCellValue(text string) any {
+	if text == "" {
+		return 0
+	}
+
+	return text
+}
+
+type ipuzClue struct {
+	label string // The clue number, empty if the clue is not numbered
+	text  string // The clue text
+}
+
+// ipuzClueLists parses the across and down clue lists, other directions are reported as warnings.
+func ipuzClueLists(rawClues map[string][]json.RawMessage, warnings *conversionWarnings) (map[Direction][]ipuzClue, error) {
+	found := map[Direction][]ipuzClue{}
+
+	for _, key := range slices.Sorted(maps.Keys(rawClues)) {
+		list := rawClues[key]
+		name, _, _ := strings.Cut(key, ":")
+
+		var dir Direction
+		switch strings.ToLower(name) {
+		case "across":
+			dir = Across
+		case "down":
+			dir = Down
+		default:
+			warnings.add("clues", fmt.Sprintf("%s clues are not supported and were dropped", name))
+			continue
+		}
+
+		for _, raw := range list {
+			clue, err := parseIpuzClue(raw, warnings)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse ipuz clue: %w", err)
+			}
+
+			found[dir] = append(found[dir], clue)
+		}
+	}
+
+	return found, nil
+}
+
+// ipuzClues matches the ipuz clues to the words in the puzzle board.
+// Words without a clue are given an empty clue, clues without a word are dropped, both are reported as warnings.
+func ipuzClues(puzzle *Puzzle, found map[Direction][]ipuzClue, labels [][]string, warnings *conversionWarnings) Clues {
+	var clues Clues
+	used := map[Direction][]bool{
+		Across: make([]bool, len(found[Across])),
+		Down:   make([]bool, len(found[Down])),
+	}
+	position := map[Direction]int{}
+
+	for _, word := range puzzle.Board.GetWords() {
+		label := labels[word.StartY][word.StartX]
+		if label == "" {
+			label = strconv.Itoa(word.Num)
+		} else if label != strconv.Itoa(word.Num) {
+			warnings.add("numbering", "the ipuz grid numbering does not match the standard numbering and was replaced")
+		}
+
+		index := slices.IndexFunc(found[word.Direction], func(clue ipuzClue) bool {
+			return clue.label == label
+		})
+
+		// unnumbered clues are matched in order
+		if index == -1 {
+			i := position[word.Direction]
+			if i < len(found[word.Direction]) && found[word.Direction][i].label == "" {
+				index = i
+			}
+		}
+		position[word.Direction]++
+
+		text := ""
+		if index == -1 || used[word.Direction][index] {
+			warnings.add("clues", fmt.Sprintf("no clue was found for %d %s and an empty clue was added", word.Num, directionName(word.Direction)))
+		} else {
+			used[word.Direction][index] = true
+			text = importText(puzzle, found[word.Direction][index].text, "clues", warnings)
+		}
+
+		clues = append(clues, NewClue(text, word.Num, word.StartX, word.StartY, word.Direction))
+	}
+
+	for _, dir := range []Direction{Across, Down} {
+		for i, ok := range used[dir] {
+			if !ok {
+				warnings.add("clues", fmt.Sprintf("%s %s clue does not match a word in the grid and was dropped", found[dir][i].label, directionName(dir)))
+			}
+		}
+	}
+
+	return clues
+}
+
+// parseIpuzClue normalizes a clue that is either a string, a [number, clue] pair, or a clue object.
+func parseIpuzClue(raw json.RawMessage, warnings *conversionWarnings) (ipuzClue, error) {
+	raw = bytes.TrimSpace(raw)
+
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return ipuzClue{"", text}, nil
+	}
+
+	var pair []json.RawMessage
+	if json.Unmarshal(raw, &pair) == nil {
+		if len(pair) != 2 {
+			return ipuzClue{}, UnreadableDataError
+		}
+
+		err := json.Unmarshal(pair[1], &text)
+		if err != nil {
+			return ipuzClue{}, err
+		}
+
+		return ipuzClue{ipuzScalar(pair[0], ""), text}, nil
+	}
+
+	var obj map[string]json.RawMessage
+	err := json.Unmarshal(raw, &obj)
+	if err != nil {
+		return ipuzClue{}, err
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(obj)) {
+		switch key {
+		case "number", "clue":
+		case "enumeration":
+			warnings.add("enumerations", "clue enumerations are not supported and were dropped")
+		default:
+			warnings.add("clues", fmt.Sprintf("the %q clue property is not supported and was dropped", key))
+		}
+	}
+
+	if raw, ok := obj["clue"]; ok {
+		err = json.Unmarshal(raw, &text)
+		if err != nil {
+			return ipuzClue{}, err
+		}
+	}
+
+	return ipuzClue{ipuzScalar(obj["number"], ""), text}, nil
+}
+
+// directionName returns the name of the direction as it is written in clue lists.
+func directionName(dir Direction) string {
+	if dir == Down {
+		return "Down"
+	}
+
+	return "Across"
+}
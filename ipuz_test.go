@@ -0,0 +1,172 @@
+package puz_test
+
+import (
+	puz "github.com/cqb13/puz-parser"
+	"slices"
+	"testing"
+)
+
+const sampleIpuz = `{
+	"version": "http://ipuz.org/v2",
+	"kind": ["http://ipuz.org/crossword#1"],
+	"title": "Sample",
+	"author": "Tester",
+	"copyright": "© 2026",
+	"dimensions": {"width": 3, "height": 3},
+	"puzzle": [
+		[{"cell": 1, "style": {"shapebg": "circle"}}, 2, 3],
+		[4, 0, 0],
+		[5, 0, {"cell": 0, "style": {"barred": "R"}}]
+	],
+	"solution": [
+		["C", "A", "T"],
+		["A", {"value": "HEART"}, "E"],
+		["B", "E", "D"]
+	],
+	"saved": [
+		["C", null, 0],
+		[0, "HEART", 0],
+		[0, 0, 0]
+	],
+	"clues": {
+		"Across": [[1, "Pet"], [4, "Love rebus"], {"number": 5, "clue": "Sleep here", "enumeration": "3"}],
+		"Down": [[1, "Taxi"], [2, "Ape, maybe"], [3, "Talk"]]
+	}
+}`
+
+func TestDecodeIpuz(t *testing.T) {
+	puzzle, warnings, err := puz.DecodeIpuz([]byte(sampleIpuz))
+	if err != nil {
+		t.Fatalf("Failed to decode ipuz: %v", err)
+	}
+
+	if puzzle.Title != "Sample" || puzzle.Author != "Tester" {
+		t.Fatalf("Found unexpected title or author, found %s by %s", puzzle.Title, puzzle.Author)
+	}
+
+	if puzzle.Copyright != "\xa9 2026" {
+		t.Fatalf("Copyright was not converted to ISO-8859-1, found %q", puzzle.Copyright)
+	}
+
+	word, _ := puzzle.Board.GetWord(0, 1, puz.Across)
+	if word != "AHE" {
+		t.Fatalf("Failed to load answers, expected AHE, found %s", word)
+	}
+
+	key := puzzle.Board[1][1].RebusKey
+	if key == 0 || !puzzle.HasExtraSection(puz.RebusSection) || !puzzle.HasExtraSection(puz.RebusTableSection) {
+		t.Fatalf("Failed to convert the rebus cell")
+	}
+
+	if len(puzzle.Extras.RebusTable) != 1 || puzzle.Extras.RebusTable[0].Value != "HEART" {
+		t.Fatalf("Failed to add HEART to the rebus table, found %v", puzzle.Extras.RebusTable)
+	}
+
+	if len(puzzle.Extras.UserRebusTable) != 1 || puzzle.Board[1][1].Guess != 'H' {
+		t.Fatalf("Failed to load the rebus guess")
+	}
+
+	if puzzle.Board[0][0].Markup != puz.SquareCircled || !puzzle.HasExtraSection(puz.MarkupBoardSection) {
+		t.Fatalf("Failed to convert the circled cell")
+	}
+
+	if puzzle.Board[0][0].Guess != 'C' || puzzle.Board[0][1].Guess != puz.EmptyStateSquare {
+		t.Fatalf("Failed to load saved guesses")
+	}
+
+	if len(puzzle.Clues()) != 6 || puzzle.ExpectedClues() != 6 {
+		t.Fatalf("Expected 6 clues, found %d", len(puzzle.Clues()))
+	}
+
+	clue, ok := puzzle.GetClueByNum(5, puz.Across)
+	if !ok || clue.Clue != "Sleep here" {
+		t.Fatalf("Failed to load clue from a clue object")
+	}
+
+	for _, feature := range []string{"enumerations", "barred edges"} {
+		found := slices.ContainsFunc(warnings, func(w puz.ConversionWarning) bool {
+			return w.Feature == feature
+		})
+
+		if !found {
+			t.Errorf("Expected a %s warning, found %v", feature, warnings)
+		}
+	}
+
+	if _, err := puz.EncodePuz(puzzle); err != nil {
+		t.Fatalf("Failed to encode converted puzzle: %v", err)
+	}
+}
+
+func TestDecodeIpuzWrongKind(t *testing.T) {
+	_, _, err := puz.DecodeIpuz([]byte(`{"version": "http://ipuz.org/v2", "kind": ["http://ipuz.org/sudoku#1"]}`))
+	if err != puz.UnsupportedIpuzKindError {
+		t.Fatalf("Expected UnsupportedIpuzKindError, found %v", err)
+	}
+}
+
+func TestIpuzRoundTrip(t *testing.T) {
+	testCases := []string{
+		"Crossword.puz",
+		"Crossword-EXT-Rebus.puz",
+		"NYT-Nov2193.puz",
+		"washpost.puz",
+	}
+
+	for _, name := range testCases {
+		t.Run(name, func(t *testing.T) {
+			original, err := puz.DecodePuz(loadFile(t, name))
+			if err != nil {
+				t.Fatalf("Failed to decode %s: %v", name, err)
+			}
+
+			data, _, err := puz.EncodeIpuz(original)
+			if err != nil {
+				t.Fatalf("Failed to encode %s as ipuz: %v", name, err)
+			}
+
+			converted, _, err := puz.DecodeIpuz(data)
+			if err != nil {
+				t.Fatalf("Failed to decode ipuz for %s: %v", name, err)
+			}
+
+			if converted.Title != original.Title || converted.Author != original.Author || converted.Copyright != original.Copyright || converted.Notes != original.Notes {
+				t.Errorf("Strings changed during ipuz round trip")
+			}
+
+			if converted.PuzzleType != original.PuzzleType {
+				t.Errorf("Puzzle type changed during ipuz round trip")
+			}
+
+			for y := range original.Board {
+				for x := range original.Board[y] {
+					a := original.Board[y][x]
+					b := converted.Board[y][x]
+
+					if a.Answer != b.Answer || a.Guess != b.Guess || (a.RebusKey == 0) != (b.RebusKey == 0) {
+						t.Fatalf("Cell x: %d y: %d changed during ipuz round trip", x, y)
+					}
+				}
+			}
+
+			if !slices.Equal(original.Clues(), converted.Clues()) {
+				t.Errorf("Clues changed during ipuz round trip")
+			}
+
+			if _, err := puz.EncodePuz(converted); err != nil {
+				t.Fatalf("Failed to encode converted %s: %v", name, err)
+			}
+		})
+	}
+}
+
+func TestEncodeIpuzScrambled(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword-Scrambled.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword-Scrambled.puz: %v", err)
+	}
+
+	if _, _, err := puz.EncodeIpuz(puzzle); err != puz.PuzzleIsScrambledError {
+		t.Fatalf("Expected PuzzleIsScrambledError, found %v", err)
+	}
+}
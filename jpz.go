@@ -0,0 +1,633 @@
+package puz
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const jpzAppletNamespace string = "http://crossword.info/xml/crossword-compiler"
+const jpzPuzzleNamespace string = "http://crossword.info/xml/rectangular-puzzle"
+const jpzZipEntryName string = "puzzle.jpz"
+
+var zipMagic = []byte("PK\x03\x04")
+var jpzTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+type jpzApplet struct {
+	XMLName xml.Name  `xml:"crossword-compiler"`
+	Xmlns   string    `xml:"xmlns,attr"`
+	Puzzle  jpzPuzzle `xml:"rectangular-puzzle"`
+}
+
+type jpzPuzzle struct {
+	Xmlns        string       `xml:"xmlns,attr,omitempty"`
+	Alphabet     string       `xml:"alphabet,attr,omitempty"`
+	Metadata     jpzMetadata  `xml:"metadata"`
+	Instructions string       `xml:"instructions,omitempty"`
+	Crossword    jpzCrossword `xml:"crossword"`
+}
+
+type jpzMetadata struct {
+	Title       string `xml:"title,omitempty"`
+	Creator     string `xml:"creator,omitempty"`
+	Copyright   string `xml:"copyright,omitempty"`
+	Description string `xml:"description,omitempty"`
+}
+
+type jpzCrossword struct {
+	Grid  jpzGrid    `xml:"grid"`
+	Words []jpzWord  `xml:"word"`
+	Clues []jpzClues `xml:"clues"`
+}
+
+type jpzGrid struct {
+	Width  int       `xml:"width,attr"`
+	Height int       `xml:"height,attr"`
+	Cells  []jpzCell `xml:"cell"`
+}
+
+type jpzCell struct {
+	X               int        `xml:"x,attr"`
+	Y               int        `xml:"y,attr"`
+	Type            string     `xml:"type,attr,omitempty"`
+	Solution        string     `xml:"solution,attr,omitempty"`
+	Number          string     `xml:"number,attr,omitempty"`
+	SolveState      string     `xml:"solve-state,attr,omitempty"`
+	BackgroundShape string     `xml:"background-shape,attr,omitempty"`
+	Other           []xml.Attr `xml:",any,attr"`
+}
+
+type jpzWord struct {
+	ID    string         `xml:"id,attr"`
+	X     string         `xml:"x,attr,omitempty"`
+	Y     string         `xml:"y,attr,omitempty"`
+	Cells []jpzWordCells `xml:"cells"`
+}
+
+type jpzWordCells struct {
+	X string `xml:"x,attr"`
+	Y string `xml:"y,attr"`
+}
+
+type jpzClues struct {
+	Title jpzInner  `xml:"title"`
+	Clues []jpzClue `xml:"clue"`
+}
+
+type jpzInner struct {
+	Content string `xml:",innerxml"`
+}
+
+type jpzClue struct {
+	Word    string `xml:"word,attr"`
+	Number  string `xml:"number,attr"`
+	Format  string `xml:"format,attr,omitempty"`
+	Content string `xml:",innerxml"`
+}
+
+// jpzSlot identifies a word in the grid by its first cell and direction
+type jpzSlot struct {
+	x   int
+	y   int
+	dir Direction
+}
+
+// DecodeJpz parses a Crossword Compiler jpz puzzle and returns Puzzle.
+//
+// Both zipped jpz files and plain rectangular-puzzle XML are accepted.
+// Multi-letter solutions are converted to rebus entries, circled cells to SquareCircled markup, and clues are matched to the grid through their word ranges.
+// Any jpz data that can not be stored in a puz file, such as clue formatting and bars, is reported in the returned warnings.
+func DecodeJpz(data []byte) (*Puzzle, []ConversionWarning, error) {
+	var warnings conversionWarnings
+
+	xmlData, err := unzipJpz(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to unzip jpz: %w", err)
+	}
+
+	file, err := parseJpzXML(xmlData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to parse jpz xml: %w", err)
+	}
+
+	grid := file.Crossword.Grid
+	if grid.Width < 1 || grid.Width > 255 || grid.Height < 1 || grid.Height > 255 {
+		return nil, nil, InvalidJpzGridError
+	}
+
+	puzzle := NewPuzzle(uint8(grid.Width), uint8(grid.Height))
+
+	texts := []string{file.Metadata.Title, file.Metadata.Creator, file.Metadata.Copyright, file.Instructions, file.Metadata.Description}
+	for _, list := range file.Crossword.Clues {
+		for _, clue := range list.Clues {
+			texts = append(texts, clue.Content)
+		}
+	}
+	puzzle.setTextVersion(texts)
+
+	labels := make([][]string, grid.Height)
+	for y := range grid.Height {
+		labels[y] = make([]string, grid.Width)
+	}
+
+	seen := make([]bool, grid.Width*grid.Height)
+	answerMissing := false
+
+	for _, cell := range grid.Cells {
+		x := cell.X - 1
+		y := cell.Y - 1
+
+		if !puzzle.Board.inBounds(x, y) {
+			return nil, nil, InvalidJpzGridError
+		}
+		seen[y*grid.Width+x] = true
+
+		for _, attr := range cell.Other {
+			switch attr.Name.Local {
+			case "top-bar", "bottom-bar", "left-bar", "right-bar":
+				warnings.add("bars", "barred cell edges are not supported and were dropped")
+			default:
+				warnings.add("cell attributes", fmt.Sprintf("the %q cell attribute is not supported and was dropped", attr.Name.Local))
+			}
+		}
+
+		if cell.Type == "block" || cell.Type == "void" {
+			if cell.Type == "void" {
+				warnings.add("void cells", "cells outside of the puzzle were converted to solid squares")
+			}
+
+			puzzle.Board[y][x].Answer = SolidSquare
+			puzzle.Board[y][x].Guess = SolidSquare
+			continue
+		}
+
+		if cell.Type != "" && cell.Type != "letter" {
+			warnings.add("cell types", fmt.Sprintf("%s cells are not supported and were converted to letter cells", cell.Type))
+		}
+
+		labels[y][x] = cell.Number
+
+		switch cell.BackgroundShape {
+		case "":
+		case "circle":
+			puzzle.Board[y][x].SetCircled(true)
+			puzzle.AddExtraSection(MarkupBoardSection)
+		default:
+			warnings.add("shapes", "background shapes other than circles are not supported and were dropped")
+		}
+
+		answer := importText(puzzle, strings.ToUpper(cell.Solution), "solution", &warnings)
+		switch len(answer) {
+		case 0:
+			answerMissing = true
+		case 1:
+			puzzle.Board[y][x].Answer = answer[0]
+		default:
+			err = puzzle.SetRebus(x, y, answer)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		guess := importText(puzzle, strings.ToUpper(cell.SolveState), "solve state", &warnings)
+		switch len(guess) {
+		case 0:
+		case 1:
+			puzzle.Board[y][x].Guess = guess[0]
+		default:
+			err = puzzle.SetUserRebus(x, y, guess)
+			if err != nil {
+				puzzle.Board[y][x].Guess = guess[0]
+				warnings.add("solve state", fmt.Sprintf("multi-letter guess at %d,%d is not in a rebus cell and was shortened to its first letter", x, y))
+			}
+		}
+	}
+
+	for i, ok := range seen {
+		if !ok {
+			puzzle.Board[i/grid.Width][i%grid.Width].Answer = SolidSquare
+			puzzle.Board[i/grid.Width][i%grid.Width].Guess = SolidSquare
+			warnings.add("missing cells", "cells missing from the grid were converted to solid squares")
+		}
+	}
+
+	if answerMissing {
+		warnings.add("solution", "some cells have no answer and were left empty")
+	}
+
+	puzzle.Title = importText(puzzle, file.Metadata.Title, "title", &warnings)
+	puzzle.Author = importText(puzzle, file.Metadata.Creator, "author", &warnings)
+	puzzle.Copyright = importText(puzzle, file.Metadata.Copyright, "copyright", &warnings)
+
+	notes := file.Instructions
+	if notes == "" {
+		notes = file.Metadata.Description
+	} else if file.Metadata.Description != "" {
+		warnings.add("description", "the description was dropped in favor of the instructions")
+	}
+	puzzle.Notes = importText(puzzle, notes, "notes", &warnings)
+
+	puzzle.SetClues(jpzMatchClues(puzzle, file.Crossword, labels, &warnings))
+
+	return puzzle, warnings, nil
+}
+
+// EncodeJpz encodes the puzzle as Crossword Compiler XML, compressed as a zip archive when zipped is true.
+//
+// Data that jpz can not hold, such as the timer and incorrect or given markup, is reported in the returned warnings.
+// Returns PuzzleIsScrambledError if the puzzle is scrambled because the answers can not be exported.
+func EncodeJpz(puzzle *Puzzle, zipped bool) ([]byte, []ConversionWarning, error) {
+	var warnings conversionWarnings
+
+	if puzzle.Scrambled() {
+		return nil, nil, PuzzleIsScrambledError
+	}
+
+	if puzzle.PuzzleType == Diagramless {
+		warnings.add("diagramless", "the diagramless puzzle type is not supported by jpz and was dropped")
+	}
+
+	if puzzle.HasExtraSection(TimerSection) {
+		warnings.add("timer", "the timer is not supported by jpz and was dropped")
+	}
+
+	width := puzzle.Board.Width()
+	height := puzzle.Board.Height()
+
+	numbers := puzzle.Board.cellNumbers()
+
+	var crossword jpzCrossword
+	crossword.Grid = jpzGrid{width, height, nil}
+
+	for y := range height {
+		for x := range width {
+			cell := puzzle.Board[y][x]
+			out := jpzCell{X: x + 1, Y: y + 1}
+
+			if puzzle.Board.IsSolidSquare(x, y) {
+				out.Type = "block"
+				crossword.Grid.Cells = append(crossword.Grid.Cells, out)
+				continue
+			}
+
+			out.Solution = exportCellText(puzzle, cell.Answer, cell.RebusKey, puzzle.Extras.RebusTable, EmptySolutionSquare)
+			out.SolveState = exportCellText(puzzle, cell.Guess, cell.RebusKey, puzzle.Extras.UserRebusTable, EmptyStateSquare)
+
+			if numbers[y][x] != 0 {
+				out.Number = strconv.Itoa(numbers[y][x])
+			}
+
+			if cell.IsCircled() {
+				out.BackgroundShape = "circle"
+			}
+
+			if cell.Markup&^SquareCircled != 0 {
+				warnings.add("markup", "incorrect and given markup is not supported by jpz and was dropped")
+			}
+
+			crossword.Grid.Cells = append(crossword.Grid.Cells, out)
+		}
+	}
+
+	for _, dir := range []Direction{Across, Down} {
+		list := jpzClues{jpzInner{"<b>" + directionName(dir) + "</b>"}, nil}
+
+		for _, clue := range puzzle.GetCluesByDirection(dir) {
+			word, ok := puzzle.Board.GetWord(clue.StartX, clue.StartY, dir)
+			if !ok {
+				warnings.add("clues", fmt.Sprintf("%d %s clue does not start a word and was dropped", clue.Num, directionName(dir)))
+				continue
+			}
+
+			id := strconv.Itoa(len(crossword.Words) + 1)
+			out := jpzWord{ID: id}
+
+			if dir == Across {
+				out.X = fmt.Sprintf("%d-%d", clue.StartX+1, clue.StartX+len(word))
+				out.Y = strconv.Itoa(clue.StartY + 1)
+			} else {
+				out.X = strconv.Itoa(clue.StartX + 1)
+				out.Y = fmt.Sprintf("%d-%d", clue.StartY+1, clue.StartY+len(word))
+			}
+
+			var text strings.Builder
+			xml.EscapeText(&text, []byte(puzzle.textToUTF8(clue.Clue)))
+
+			crossword.Words = append(crossword.Words, out)
+			list.Clues = append(list.Clues, jpzClue{id, strconv.Itoa(clue.Num), "", text.String()})
+		}
+
+		crossword.Clues = append(crossword.Clues, list)
+	}
+
+	applet := jpzApplet{
+		Xmlns: jpzAppletNamespace,
+		Puzzle: jpzPuzzle{
+			Xmlns: jpzPuzzleNamespace,
+			Metadata: jpzMetadata{
+				Title:     puzzle.textToUTF8(puzzle.Title),
+				Creator:   puzzle.textToUTF8(puzzle.Author),
+				Copyright: puzzle.textToUTF8(puzzle.Copyright),
+			},
+			Instructions: puzzle.textToUTF8(puzzle.Notes),
+			Crossword:    crossword,
+		},
+	}
+
+	body, err := xml.MarshalIndent(applet, "", "\t")
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to encode jpz xml: %w", err)
+	}
+
+	data := append([]byte(xml.Header), body...)
+	data = append(data, '\n')
+
+	if !zipped {
+		return data, warnings, nil
+	}
+
+	var out bytes.Buffer
+	archive := zip.NewWriter(&out)
+
+	entry, err := archive.Create(jpzZipEntryName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to zip jpz: %w", err)
+	}
+
+	_, err = entry.Write(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to zip jpz: %w", err)
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("Failed to zip jpz: %w", err)
+	}
+
+	return out.Bytes(), warnings, nil
+}
+
+// unzipJpz returns the first file in a zipped jpz, or data unchanged if it is not a zip archive.
+func unzipJpz(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, zipMagic) {
+		return data, nil
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		return io.ReadAll(reader)
+	}
+
+	return nil, MissingJpzPuzzleError
+}
+
+// parseJpzXML finds and decodes the rectangular-puzzle element, which may be the root or wrapped in an applet element.
+func parseJpzXML(data []byte) (*jpzPuzzle, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = jpzCharsetReader
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, MissingJpzPuzzleError
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "rectangular-puzzle" {
+			continue
+		}
+
+		var file jpzPuzzle
+		err = decoder.DecodeElement(&file, &start)
+		if err != nil {
+			return nil, err
+		}
+
+		return &file, nil
+	}
+}
+
+// jpzCharsetReader converts ISO-8859-1 and Windows-1252 documents into UTF-8 for the XML decoder.
+func jpzCharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1":
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+
+		return strings.NewReader(latin1ToUTF8(string(data))), nil
+	case "windows-1252":
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return nil, err
+		}
+
+		return strings.NewReader(windows1252ToUTF8(string(data))), nil
+	}
+
+	return nil, UnsupportedJpzCharsetError
+}
+
+// parseJpzRange expands a jpz coordinate list such as "3", "1-5", or "1-3,5" into 0 based indices.
+func parseJpzRange(value string) ([]int, bool) {
+	var indices []int
+
+	for part := range strings.SplitSeq(value, ",") {
+		startStr, endStr, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		if !isRange {
+			endStr = startStr
+		}
+
+		start, err := strconv.Atoi(startStr)
+		if err != nil {
+			return nil, false
+		}
+
+		end, err := strconv.Atoi(endStr)
+		if err != nil {
+			return nil, false
+		}
+
+		step := 1
+		if end < start {
+			step = -1
+		}
+
+		for i := start; ; i += step {
+			indices = append(indices, i-1)
+			if i == end {
+				break
+			}
+		}
+	}
+
+	return indices, true
+}
+
+// jpzWordSlot returns the first cell and direction of a jpz word, ok is false if the word is not a straight line of cells.
+func jpzWordSlot(word jpzWord) (jpzSlot, bool) {
+	var xs []int
+	var ys []int
+
+	ranges := append([]jpzWordCells{{word.X, word.Y}}, word.Cells...)
+	for _, r := range ranges {
+		if r.X == "" && r.Y == "" {
+			continue
+		}
+
+		rangeX, okX := parseJpzRange(r.X)
+		rangeY, okY := parseJpzRange(r.Y)
+		if !okX || !okY {
+			return jpzSlot{}, false
+		}
+
+		// a single coordinate applies to every cell in the other range
+		for len(rangeX) < len(rangeY) {
+			rangeX = append(rangeX, rangeX[0])
+		}
+		for len(rangeY) < len(rangeX) {
+			rangeY = append(rangeY, rangeY[0])
+		}
+
+		xs = append(xs, rangeX...)
+		ys = append(ys, rangeY...)
+	}
+
+	if len(xs) < 2 {
+		return jpzSlot{}, false
+	}
+
+	for i := 1; i < len(xs); i++ {
+		if xs[i] == xs[i-1]+1 && ys[i] == ys[0] {
+			continue
+		}
+
+		if ys[i] == ys[i-1]+1 && xs[i] == xs[0] {
+			continue
+		}
+
+		return jpzSlot{}, false
+	}
+
+	dir := Direction(Across)
+	if xs[1] == xs[0] {
+		dir = Down
+	}
+
+	return jpzSlot{xs[0], ys[0], dir}, true
+}
+
+// jpzClueText strips formatting tags and entities from the contents of a clue.
+func jpzClueText(content string, warnings *conversionWarnings) string {
+	text := jpzTagPattern.ReplaceAllString(content, "")
+	if text != content {
+		warnings.add("formatting", "clue formatting is not supported and was removed")
+	}
+
+	return strings.TrimSpace(html.UnescapeString(text))
+}
+
+// jpzMatchClues matches the jpz clues to the words in the puzzle board.
+//
+// Clues are matched by the position of their word, falling back to their number and the direction in the clue list title.
+// Words without a clue are given an empty clue, clues without a word are dropped, both are reported as warnings.
+func jpzMatchClues(puzzle *Puzzle, crossword jpzCrossword, labels [][]string, warnings *conversionWarnings) Clues {
+	slots := map[string]jpzSlot{}
+	for _, word := range crossword.Words {
+		slot, ok := jpzWordSlot(word)
+		if ok {
+			slots[word.ID] = slot
+		} else {
+			warnings.add("words", fmt.Sprintf("word %s is not a straight line of cells and was dropped", word.ID))
+		}
+	}
+
+	byPos := map[jpzSlot]string{}
+	byNum := map[Direction]map[string]string{Across: {}, Down: {}}
+	unmatched := map[jpzSlot]bool{}
+
+	for _, list := range crossword.Clues {
+		title := strings.ToLower(jpzTagPattern.ReplaceAllString(list.Title.Content, ""))
+
+		for _, clue := range list.Clues {
+			if clue.Format != "" {
+				warnings.add("enumerations", "clue formats are not supported and were dropped")
+			}
+
+			text := jpzClueText(clue.Content, warnings)
+
+			slot, ok := slots[clue.Word]
+			if ok {
+				byPos[slot] = text
+				unmatched[slot] = true
+				continue
+			}
+
+			if strings.Contains(title, "across") {
+				byNum[Across][clue.Number] = text
+			} else if strings.Contains(title, "down") {
+				byNum[Down][clue.Number] = text
+			} else {
+				warnings.add("clues", fmt.Sprintf("clue %s could not be matched to a word and was dropped", clue.Number))
+			}
+		}
+	}
+
+	var clues Clues
+
+	for _, word := range puzzle.Board.GetWords() {
+		slot := jpzSlot{word.StartX, word.StartY, word.Direction}
+
+		label := labels[word.StartY][word.StartX]
+		if label == "" {
+			label = strconv.Itoa(word.Num)
+		} else if label != strconv.Itoa(word.Num) {
+			warnings.add("numbering", "the jpz grid numbering does not match the standard numbering and was replaced")
+		}
+
+		text, ok := byPos[slot]
+		if ok {
+			delete(unmatched, slot)
+		} else {
+			text, ok = byNum[word.Direction][label]
+			delete(byNum[word.Direction], label)
+		}
+
+		if !ok {
+			warnings.add("clues", fmt.Sprintf("no clue was found for %d %s and an empty clue was added", word.Num, directionName(word.Direction)))
+		}
+
+		clues = append(clues, NewClue(importText(puzzle, text, "clues", warnings), word.Num, word.StartX, word.StartY, word.Direction))
+	}
+
+	if len(unmatched) > 0 || len(byNum[Across]) > 0 || len(byNum[Down]) > 0 {
+		warnings.add("clues", "clues that do not match a word in the grid were dropped")
+	}
+
+	return clues
+}
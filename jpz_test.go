@@ -0,0 +1,186 @@
+package puz_test
+
+import (
+	"bytes"
+	"errors"
+	puz "github.com/cqb13/puz-parser"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sampleJpz = `<?xml version="1.0" encoding="UTF-8"?>
+<crossword-compiler-applet xmlns="http://crossword.info/xml/crossword-compiler-applet">
+<rectangular-puzzle xmlns="http://crossword.info/xml/rectangular-puzzle" alphabet="ABCDEFGHIJKLMNOPQRSTUVWXYZ">
+<metadata><title>Sample</title><creator>Tester</creator><copyright>© 2026</copyright></metadata>
+<crossword>
+<grid width="3" height="3">
+<cell x="1" y="1" solution="C" number="1" background-shape="circle"/>
+<cell x="2" y="1" solution="A" number="2" solve-state="A"/>
+<cell x="3" y="1" solution="T" number="3"/>
+<cell x="1" y="2" solution="A" number="4"/>
+<cell x="2" y="2" solution="HEART"/>
+<cell x="3" y="2" solution="E"/>
+<cell x="1" y="3" solution="B" number="5"/>
+<cell x="2" y="3" solution="E"/>
+<cell x="3" y="3" type="block"/>
+</grid>
+<word id="1" x="1-3" y="1"/>
+<word id="2" x="1-3" y="2"/>
+<word id="3" x="1-2" y="3"/>
+<word id="4" x="1" y="1-3"/>
+<word id="5"><cells x="2" y="1"/><cells x="2" y="2"/><cells x="2" y="3"/></word>
+<word id="6" x="3" y="1-2"/>
+<clues><title><b>Across</b></title>
+<clue word="1" number="1">Pet</clue>
+<clue word="2" number="4" format="5">Love <i>rebus</i> &amp; more</clue>
+<clue word="3" number="5">Taxi</clue>
+</clues>
+<clues><title><b>Down</b></title>
+<clue word="4" number="1">Taxi</clue>
+<clue word="5" number="2">Ape, maybe</clue>
+<clue word="6" number="3">Tee</clue>
+</clues>
+</crossword>
+</rectangular-puzzle>
+</crossword-compiler-applet>`
+
+func TestDecodeJpz(t *testing.T) {
+	puzzle, warnings, err := puz.DecodeJpz([]byte(sampleJpz))
+	if err != nil {
+		t.Fatalf("Failed to decode jpz: %v", err)
+	}
+
+	if puzzle.Title != "Sample" || puzzle.Author != "Tester" || puzzle.Copyright != "\xa9 2026" {
+		t.Fatalf("Failed to load metadata, found %q, %q, %q", puzzle.Title, puzzle.Author, puzzle.Copyright)
+	}
+
+	if !puzzle.Board.IsSolidSquare(2, 2) {
+		t.Fatalf("Failed to load block cell")
+	}
+
+	if puzzle.Board[1][1].RebusKey == 0 || len(puzzle.Extras.RebusTable) != 1 || puzzle.Extras.RebusTable[0].Value != "HEART" {
+		t.Fatalf("Failed to convert multi-letter solution to a rebus")
+	}
+
+	if puzzle.Board[0][0].Markup != puz.SquareCircled || !puzzle.HasExtraSection(puz.MarkupBoardSection) {
+		t.Fatalf("Failed to convert the circled cell")
+	}
+
+	if puzzle.Board[0][1].Guess != 'A' {
+		t.Fatalf("Failed to load solve state")
+	}
+
+	clue, ok := puzzle.GetClueByPos(0, 1, puz.Across)
+	if !ok || clue.Clue != "Love rebus & more" {
+		t.Fatalf("Failed to load clue with formatting, found %v", clue)
+	}
+
+	clue, ok = puzzle.GetClueByPos(1, 0, puz.Down)
+	if !ok || clue.Clue != "Ape, maybe" {
+		t.Fatalf("Failed to match clue through word cells")
+	}
+
+	if len(puzzle.Clues()) != 6 {
+		t.Fatalf("Expected 6 clues, found %d", len(puzzle.Clues()))
+	}
+
+	for _, feature := range []string{"formatting", "enumerations"} {
+		found := slices.ContainsFunc(warnings, func(w puz.ConversionWarning) bool {
+			return w.Feature == feature
+		})
+
+		if !found {
+			t.Errorf("Expected a %s warning, found %v", feature, warnings)
+		}
+	}
+
+	if _, err := puz.EncodePuz(puzzle); err != nil {
+		t.Fatalf("Failed to encode converted puzzle: %v", err)
+	}
+}
+
+func TestJpzRoundTrip(t *testing.T) {
+	testCases := []string{
+		"Crossword.puz",
+		"Crossword-EXT-Rebus.puz",
+		"NYT-Nov2193.puz",
+		"washpost.puz",
+	}
+
+	for _, name := range testCases {
+		for _, zipped := range []bool{false, true} {
+			t.Run(name, func(t *testing.T) {
+				original, err := puz.DecodePuz(loadFile(t, name))
+				if err != nil {
+					t.Fatalf("Failed to decode %s: %v", name, err)
+				}
+
+				data, _, err := puz.EncodeJpz(original, zipped)
+				if err != nil {
+					t.Fatalf("Failed to encode %s as jpz: %v", name, err)
+				}
+
+				if zipped != bytes.HasPrefix(data, []byte("PK")) {
+					t.Fatalf("Expected zipped to be %t for %s", zipped, name)
+				}
+
+				converted, _, err := puz.DecodeJpz(data)
+				if err != nil {
+					t.Fatalf("Failed to decode jpz for %s: %v", name, err)
+				}
+
+				if converted.Title != original.Title || converted.Author != original.Author || converted.Copyright != original.Copyright || converted.Notes != original.Notes {
+					t.Errorf("Strings changed during jpz round trip")
+				}
+
+				for y := range original.Board {
+					for x := range original.Board[y] {
+						a := original.Board[y][x]
+						b := converted.Board[y][x]
+
+						if a.Answer != b.Answer || a.Guess != b.Guess || (a.RebusKey == 0) != (b.RebusKey == 0) {
+							t.Fatalf("Cell x: %d y: %d changed during jpz round trip", x, y)
+						}
+					}
+				}
+
+				if !slices.Equal(original.Clues(), converted.Clues()) {
+					t.Errorf("Clues changed during jpz round trip")
+				}
+
+				if _, err := puz.EncodePuz(converted); err != nil {
+					t.Fatalf("Failed to encode converted %s: %v", name, err)
+				}
+			})
+		}
+	}
+}
+
+func TestDecodeJpzMissingPuzzle(t *testing.T) {
+	_, _, err := puz.DecodeJpz([]byte(`<?xml version="1.0"?><crossword-compiler></crossword-compiler>`))
+	if err == nil {
+		t.Fatalf("Expected an error when no rectangular-puzzle is present")
+	}
+}
+
+func TestDecodeJpzCharset(t *testing.T) {
+	windows1252 := strings.Replace(sampleJpz, `encoding="UTF-8"`, `encoding="windows-1252"`, 1)
+	windows1252 = strings.Replace(windows1252, "<title>Sample</title>", "<title>\x93Sample\x94</title>", 1)
+	windows1252 = strings.Replace(windows1252, "©", "\xa9", 1)
+
+	puzzle, _, err := puz.DecodeJpz([]byte(windows1252))
+	if err != nil {
+		t.Fatalf("Failed to decode windows-1252 jpz: %v", err)
+	}
+
+	if puzzle.Title != "\u201cSample\u201d" {
+		t.Fatalf("Expected curly quotes in the title, found %q", puzzle.Title)
+	}
+
+	unsupported := strings.Replace(sampleJpz, `encoding="UTF-8"`, `encoding="Shift_JIS"`, 1)
+
+	if _, _, err := puz.DecodeJpz([]byte(unsupported)); !errors.Is(err, puz.UnsupportedJpzCharsetError) {
+		t.Fatalf("Expected UnsupportedJpzCharsetError, found %v", err)
+	}
+}
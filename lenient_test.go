@@ -0,0 +1,93 @@
+package puz_test
+
+import (
+	"bytes"
+	"errors"
+	puz "github.com/cqb13/puz-parser"
+	"testing"
+)
+
+// corruptChecksums breaks the masked low checksum and the GRBS section checksum
+func corruptChecksums(t *testing.T, name string) []byte {
+	data := bytes.Clone(loadFile(t, name))
+
+	data[16] ^= 0xFF
+
+	index := bytes.Index(data, []byte("GRBS"))
+	if index == -1 {
+		t.Fatalf("Failed to find GRBS section in %s", name)
+	}
+	data[index+6] ^= 0xFF
+
+	return data
+}
+
+func TestDecodeLenient(t *testing.T) {
+	data := corruptChecksums(t, "Crossword-EXT-Rebus.puz")
+
+	puzzle, mismatches, err := puz.DecodePuzWithOptions(data, puz.DecodeOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("Failed to decode leniently: %v", err)
+	}
+
+	if len(mismatches) != 2 {
+		t.Fatalf("Expected 2 mismatches, found %v", mismatches)
+	}
+
+	if mismatches[0].Checksum != puz.MaskedLowChecksum {
+		t.Errorf("Expected a masked low mismatch first, found %v", mismatches[0])
+	}
+
+	if mismatches[1].Checksum != puz.ExtraSectionChecksum || mismatches[1].Section != "GRBS" {
+		t.Errorf("Expected a GRBS mismatch, found %v", mismatches[1])
+	}
+
+	if mismatches[1].Expected == mismatches[1].Calculated {
+		t.Errorf("Expected and calculated values should differ, found %v", mismatches[1])
+	}
+
+	if !puzzle.HasExtraSection(puz.RebusSection) || len(puzzle.Extras.RebusTable) == 0 {
+		t.Fatalf("Failed to keep the rebus sections")
+	}
+
+	original, err := puz.DecodePuz(loadFile(t, "Crossword-EXT-Rebus.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword-EXT-Rebus.puz: %v", err)
+	}
+
+	if puzzle.Title != original.Title || puzzle.Board[0][0] != original.Board[0][0] {
+		t.Errorf("Leniently decoded puzzle differs from the original")
+	}
+}
+
+func TestDecodeStrict(t *testing.T) {
+	data := corruptChecksums(t, "Crossword-EXT-Rebus.puz")
+
+	_, err := puz.DecodePuz(data)
+
+	var sectionErr *puz.ExtraSectionChecksumMismatchError
+	if !errors.As(err, &sectionErr) {
+		t.Fatalf("Expected ExtraSectionChecksumMismatchError, found %v", err)
+	}
+
+	data = bytes.Clone(loadFile(t, "Crossword.puz"))
+	data[20] ^= 0xFF
+
+	_, _, err = puz.DecodePuzWithOptions(data, puz.DecodeOptions{})
+
+	var checksumErr *puz.ChecksumMismatchError
+	if !errors.As(err, &checksumErr) {
+		t.Fatalf("Expected ChecksumMismatchError, found %v", err)
+	}
+}
+
+func TestDecodeLenientValidFile(t *testing.T) {
+	_, mismatches, err := puz.DecodePuzWithOptions(loadFile(t, "NYT-Nov2193.puz"), puz.DecodeOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("Failed to decode NYT-Nov2193.puz: %v", err)
+	}
+
+	if len(mismatches) != 0 {
+		t.Fatalf("Expected no mismatches, found %v", mismatches)
+	}
+}
@@ -0,0 +1,61 @@
+package puz_test
+
+import (
+	puz "github.com/cqb13/puz-parser"
+	"testing"
+)
+
+func TestCheckLockedSolution(t *testing.T) {
+	name := "NYT-Locked.puz"
+
+	puzzle, err := puz.DecodePuz(loadFile(t, name))
+	if err != nil {
+		t.Fatalf("Failed to decode %s: %v", name, err)
+	}
+
+	solved, err := puz.DecodePuz(loadFile(t, name))
+	if err != nil {
+		t.Fatalf("Failed to decode %s: %v", name, err)
+	}
+
+	if err := solved.Unscramble(7844); err != nil {
+		t.Fatalf("Failed to unscramble %s: %v", name, err)
+	}
+
+	correct, err := puzzle.CheckLockedSolution()
+	if err != nil {
+		t.Fatalf("Failed to check solution: %v", err)
+	}
+
+	if correct {
+		t.Fatalf("An empty grid should not be correct")
+	}
+
+	for y := range puzzle.Board {
+		for x := range puzzle.Board[y] {
+			if !puzzle.Board.IsSolidSquare(x, y) {
+				// guesses are compared without case
+				puzzle.Board[y][x].Guess = solved.Board[y][x].Answer | 0x20
+			}
+		}
+	}
+
+	correct, err = puzzle.CheckLockedSolution()
+	if err != nil {
+		t.Fatalf("Failed to check solution: %v", err)
+	}
+
+	if !correct {
+		t.Fatalf("Expected the unscrambled answers to be correct")
+	}
+
+	puzzle.Board[0][0].Guess = 'Q'
+
+	if correct, _ := puzzle.CheckLockedSolution(); correct {
+		t.Fatalf("Expected a wrong letter to be incorrect")
+	}
+
+	if _, err := solved.CheckLockedSolution(); err != puz.PuzzleIsUnscrambledError {
+		t.Fatalf("Expected PuzzleIsUnscrambledError, found %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package puz_test
+
+import (
+	"bytes"
+	puz "github.com/cqb13/puz-parser"
+	"slices"
+	"testing"
+)
+
+func TestCellMarkup(t *testing.T) {
+	var cell puz.Cell
+
+	cell.SetCircled(true)
+	cell.SetMarkup(puz.ContentGiven, true)
+
+	if !cell.IsCircled() || !cell.HasMarkup(puz.ContentGiven) || !cell.HasMarkup(puz.SquareCircled|puz.ContentGiven) {
+		t.Fatalf("Expected the cell to be circled and given, found %x", cell.Markup)
+	}
+
+	if cell.HasMarkup(puz.CurrentlyIncorrect) {
+		t.Fatalf("Found unexpected markup")
+	}
+
+	// unknown bits are left alone
+	cell.Markup |= 0x01
+	cell.SetCircled(false)
+
+	if cell.IsCircled() || cell.Markup != puz.ContentGiven|0x01 {
+		t.Fatalf("Expected only the circle to be cleared, found %x", cell.Markup)
+	}
+}
+
+func TestBoardMarkup(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	if puzzle.Board.HasMarkup() || len(puzzle.Board.CircledCells()) != 0 {
+		t.Fatalf("Found unexpected markup")
+	}
+
+	puzzle.Board[2][1].SetCircled(true)
+	puzzle.Board[0][3].SetCircled(true)
+	puzzle.Board[0][3].SetMarkup(puz.ContentGiven, true)
+
+	circled := []puz.Position{{X: 3, Y: 0}, {X: 1, Y: 2}}
+	if !slices.Equal(puzzle.Board.CircledCells(), circled) {
+		t.Fatalf("Expected %v, found %v", circled, puzzle.Board.CircledCells())
+	}
+
+	given := puzzle.Board.CellsWithMarkup(puz.ContentGiven)
+	if !slices.Equal(given, circled[:1]) {
+		t.Fatalf("Expected %v, found %v", circled[:1], given)
+	}
+
+	encoded, err := puz.EncodePuz(puzzle)
+	if err != nil {
+		t.Fatalf("Failed to encode puzzle: %v", err)
+	}
+
+	if puzzle.HasExtraSection(puz.MarkupBoardSection) {
+		t.Errorf("Encoding should not modify the puzzle")
+	}
+
+	decoded, err := puz.DecodePuz(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode puzzle: %v", err)
+	}
+
+	if !decoded.HasExtraSection(puz.MarkupBoardSection) || !slices.Equal(decoded.Board.CircledCells(), circled) {
+		t.Fatalf("Expected markup to be encoded in a GEXT section")
+	}
+}
+
+func TestUnknownMarkupBits(t *testing.T) {
+	original := loadFile(t, "All-Sections-Sorted.puz")
+
+	index := bytes.Index(original, []byte("GEXT"))
+	if index == -1 {
+		t.Fatalf("Failed to find GEXT section")
+	}
+
+	puzzle, err := puz.DecodePuz(original)
+	if err != nil {
+		t.Fatalf("Failed to decode All-Sections-Sorted.puz: %v", err)
+	}
+
+	puzzle.Board[0][0].Markup |= 0x03
+
+	encoded, err := puz.EncodePuz(puzzle)
+	if err != nil {
+		t.Fatalf("Failed to encode puzzle: %v", err)
+	}
+
+	decoded, err := puz.DecodePuz(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode puzzle: %v", err)
+	}
+
+	if decoded.Board[0][0].Markup != puzzle.Board[0][0].Markup {
+		t.Fatalf("Expected unknown bits to be preserved, found %x", decoded.Board[0][0].Markup)
+	}
+}
@@ -0,0 +1,549 @@
+package puz
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// PageSize is the paper size RenderPDF lays the puzzle out on
+type PageSize int
+
+const (
+	LetterPage PageSize = iota // US Letter, 8.5 x 11 inches
+	A4Page                     // ISO A4, 210 x 297 millimeters
+)
+
+var pageSizeStrMap = map[PageSize]string{
+	LetterPage: "Letter",
+	A4Page:     "A4",
+}
+
+func (s PageSize) String() string {
+	return pageSizeStrMap[s]
+}
+
+// dimensions returns the width and height of the page in points.
+func (s PageSize) dimensions() (float64, float64) {
+	if s == A4Page {
+		return 595.28, 841.89
+	}
+
+	return 612, 792
+}
+
+// PDFOptions controls the output of RenderPDF
+type PDFOptions struct {
+	PageSize  PageSize // The paper size, defaults to LetterPage
+	AnswerKey bool     // Adds a second page with the solution grid
+	Notes     bool     // Prints the puzzle notes after the clues
+}
+
+const (
+	pdfMargin       = 36
+	pdfColumnGap    = 12
+	pdfColumns      = 4
+	pdfMaxFontSize  = 11
+	pdfMinFontSize  = 5
+	pdfFontStep     = 0.25
+	pdfLineSpacing  = 1.2
+	pdfTitleSize    = 16
+	pdfSubtitleSize = 10
+	pdfFooterSize   = 7
+	pdfMaxCellSize  = 36
+	pdfMaxGridShare = 0.6 // The largest share of the page height the grid can take next to the clues
+)
+
+// RenderPDF lays p out on a single printable PDF page.
+//
+// The page has the title, author, and copyright, the numbered grid in the top right, and the Across and Down clues from GetCluesByDirection in columns.
+// The clue font is shrunk until every clue fits on the page, fonts are the standard Helvetica fonts so nothing is embedded.
+// Returns PuzzleIsScrambledError if an answer key is requested for a scrambled puzzle, and PDFContentTooLargeError if the clues do not fit at the smallest font size.
+func RenderPDF(p *Puzzle, opts PDFOptions) ([]byte, error) {
+	if opts.AnswerKey && p.Scrambled() {
+		return nil, PuzzleIsScrambledError
+	}
+
+	if p.Board.Width() == 0 || p.Board.Height() == 0 {
+		return nil, PDFContentTooLargeError
+	}
+
+	width, height := opts.PageSize.dimensions()
+
+	puzzlePage, err := layoutPuzzlePage(p, opts, width, height)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := []*pdfPage{puzzlePage}
+
+	if opts.AnswerKey {
+		pages = append(pages, layoutAnswerKeyPage(p, width, height))
+	}
+
+	return writePDF(p, pages, width, height), nil
+}
+
+// A pdfLine is a line of text in the clue columns
+type pdfLine struct {
+	number  string  // The clue number, drawn bold before the text on the first line of a clue
+	text    []byte  // WinAnsi encoded text
+	font    pdfFont // The font for text
+	indent  float64 // The offset of text from the column edge
+	spacing float64 // Extra space before the line
+}
+
+// A pdfColumn is an area of the page clues flow into
+type pdfColumn struct {
+	left   float64
+	top    float64
+	bottom float64
+}
+
+func layoutPuzzlePage(p *Puzzle, opts PDFOptions, width float64, height float64) (*pdfPage, error) {
+	page := newPDFPage(height)
+	contentWidth := width - 2*pdfMargin
+
+	top := layoutPDFHeader(page, p.textToUTF8(p.Title), p.textToUTF8(p.Author), contentWidth)
+	bottom := height - pdfMargin
+
+	if p.Copyright != "" {
+		copyright := pdfText(p.textToUTF8(p.Copyright))
+		page.text(pdfRegular, pdfFooterSize, pdfMargin, bottom, copyright)
+		bottom -= pdfFooterSize * pdfLineSpacing * 1.5
+	}
+
+	columnWidth := (contentWidth - (pdfColumns-1)*pdfColumnGap) / pdfColumns
+
+	// the grid takes the top of the two right columns
+	gridSpan := 2*columnWidth + pdfColumnGap
+	cell := math.Min(gridSpan/float64(p.Board.Width()), (bottom-top)*pdfMaxGridShare/float64(p.Board.Height()))
+	cell = math.Min(cell, pdfMaxCellSize)
+	gridWidth := cell * float64(p.Board.Width())
+	gridHeight := cell * float64(p.Board.Height())
+
+	drawPDFGrid(page, p, width-pdfMargin-gridWidth, top, cell, false)
+
+	var columns []pdfColumn
+	for i := range pdfColumns {
+		column := pdfColumn{pdfMargin + float64(i)*(columnWidth+pdfColumnGap), top, bottom}
+
+		if column.left+columnWidth > width-pdfMargin-gridWidth {
+			column.top = top + gridHeight + pdfColumnGap
+		}
+
+		columns = append(columns, column)
+	}
+
+	for size := float64(pdfMaxFontSize); size >= pdfMinFontSize; size -= pdfFontStep {
+		lines := pdfClueLines(p, opts, size, columnWidth)
+
+		if flowPDFLines(page, lines, columns, size, true) {
+			flowPDFLines(page, lines, columns, size, false)
+			return page, nil
+		}
+	}
+
+	return nil, PDFContentTooLargeError
+}
+
+// layoutPDFHeader draws the title and author and returns where the body of the page starts.
+func layoutPDFHeader(page *pdfPage, title string, author string, contentWidth float64) float64 {
+	y := float64(pdfMargin)
+
+	if title != "" {
+		text := pdfText(title)
+		size := math.Min(pdfTitleSize, contentWidth/pdfBold.width(text, 1))
+		y += size
+		page.text(pdfBold, size, pdfMargin, y, text)
+		y += size * (pdfLineSpacing - 1)
+	}
+
+	if author != "" {
+		text := pdfText(author)
+		size := math.Min(pdfSubtitleSize, contentWidth/pdfRegular.width(text, 1))
+		y += size * pdfLineSpacing
+		page.text(pdfRegular, size, pdfMargin, y, text)
+	}
+
+	return y + pdfColumnGap
+}
+
+// pdfClueLines wraps the clues, and notes when requested, into lines that fit columnWidth at size.
+func pdfClueLines(p *Puzzle, opts PDFOptions, size float64, columnWidth float64) []pdfLine {
+	var lines []pdfLine
+	indent := pdfBold.width([]byte("000 "), size)
+
+	for _, dir := range []Direction{Across, Down} {
+		clues := p.GetCluesByDirection(dir)
+		slices.SortStableFunc(clues, func(a Clue, b Clue) int {
+			return a.Num - b.Num
+		})
+
+		heading := "ACROSS"
+		if dir == Down {
+			heading = "DOWN"
+		}
+
+		spacing := 0.0
+		if len(lines) > 0 {
+			spacing = size
+		}
+
+		lines = append(lines, pdfLine{"", []byte(heading), pdfBold, 0, spacing})
+
+		for _, clue := range clues {
+			for i, text := range wrapPDFText(pdfText(p.textToUTF8(clue.Clue)), size, columnWidth-indent) {
+				number := ""
+				if i == 0 {
+					number = strconv.Itoa(clue.Num)
+				}
+
+				lines = append(lines, pdfLine{number, text, pdfRegular, indent, 0})
+			}
+		}
+	}
+
+	if opts.Notes && p.Notes != "" {
+		lines = append(lines, pdfLine{"", []byte("NOTES"), pdfBold, 0, size})
+
+		for _, paragraph := range strings.Split(p.textToUTF8(p.Notes), "\n") {
+			for _, text := range wrapPDFText(pdfText(paragraph), size, columnWidth) {
+				lines = append(lines, pdfLine{"", text, pdfRegular, 0, 0})
+			}
+		}
+	}
+
+	return lines
+}
+
+// wrapPDFText splits text into lines no wider than width, words longer than a line are broken.
+func wrapPDFText(text []byte, size float64, width float64) [][]byte {
+	var lines [][]byte
+	var line []byte
+
+	for _, word := range bytes.Fields(text) {
+		candidate := word
+		if len(line) > 0 {
+			candidate = slices.Concat(line, []byte(" "), word)
+		}
+
+		if pdfRegular.width(candidate, size) <= width {
+			line = candidate
+			continue
+		}
+
+		if len(line) > 0 {
+			lines = append(lines, line)
+			line = nil
+		}
+
+		for pdfRegular.width(word, size) > width && len(word) > 1 {
+			split := 1
+			for split < len(word) && pdfRegular.width(word[:split+1], size) <= width {
+				split++
+			}
+
+			lines = append(lines, word[:split])
+			word = word[split:]
+		}
+
+		line = word
+	}
+
+	if len(line) > 0 || len(lines) == 0 {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// flowPDFLines places lines down each column in turn and reports if they all fit, nothing is drawn when dryRun is set.
+func flowPDFLines(page *pdfPage, lines []pdfLine, columns []pdfColumn, size float64, dryRun bool) bool {
+	lineHeight := size * pdfLineSpacing
+	column := 0
+	y := columns[0].top
+
+	for _, line := range lines {
+		spacing := line.spacing
+		if y == columns[column].top {
+			spacing = 0
+		}
+
+		for y+spacing+lineHeight > columns[column].bottom {
+			column++
+			if column == len(columns) {
+				return false
+			}
+
+			y = columns[column].top
+			spacing = 0
+		}
+
+		y += spacing + lineHeight
+
+		if dryRun {
+			continue
+		}
+
+		left := columns[column].left
+		if line.number != "" {
+			number := []byte(line.number)
+			page.text(pdfBold, size, left+line.indent-pdfBold.width([]byte(" "), size)-pdfBold.width(number, size), y, number)
+		}
+
+		page.text(line.font, size, left+line.indent, y, line.text)
+	}
+
+	return true
+}
+
+func layoutAnswerKeyPage(p *Puzzle, width float64, height float64) *pdfPage {
+	page := newPDFPage(height)
+	contentWidth := width - 2*pdfMargin
+
+	title := "Answer Key"
+	if p.Title != "" {
+		title = p.textToUTF8(p.Title) + " - " + title
+	}
+
+	top := layoutPDFHeader(page, title, "", contentWidth)
+	bottom := height - pdfMargin
+
+	cell := math.Min(contentWidth/float64(p.Board.Width()), (bottom-top)/float64(p.Board.Height()))
+	cell = math.Min(cell, pdfMaxCellSize)
+
+	drawPDFGrid(page, p, (width-cell*float64(p.Board.Width()))/2, top, cell, true)
+
+	return page
+}
+
+// drawPDFGrid draws the grid with its top left corner at (left, top), answers are drawn when showAnswers is set.
+func drawPDFGrid(page *pdfPage, p *Puzzle, left float64, top float64, cell float64, showAnswers bool) {
+	numbers := p.Board.cellNumbers()
+
+	page.lineWidth(0.5)
+
+	for y := range p.Board.Height() {
+		for x := range p.Board.Width() {
+			cellLeft := left + float64(x)*cell
+			cellTop := top + float64(y)*cell
+
+			if p.Board.IsSolidSquare(x, y) {
+				page.rect(cellLeft, cellTop, cell, cell, true)
+				continue
+			}
+
+			page.rect(cellLeft, cellTop, cell, cell, false)
+
+			if p.Board[y][x].IsCircled() {
+				page.circle(cellLeft+cell/2, cellTop+cell/2, cell/2-0.5)
+			}
+
+			if number := numbers[y][x]; number != 0 {
+				page.text(pdfRegular, cell*0.28, cellLeft+cell*0.06, cellTop+cell*0.3, []byte(strconv.Itoa(number)))
+			}
+
+			if !showAnswers || p.Board[y][x].Answer == EmptySolutionSquare {
+				continue
+			}
+
+			answer := pdfText(p.textToUTF8(p.Answer(x, y)))
+			size := cellFontSize(cell, pdfRegular.width(answer, 1))
+			answerWidth := pdfRegular.width(answer, size)
+			page.text(pdfRegular, size, cellLeft+(cell-answerWidth)/2, cellTop+cell*0.88, answer)
+		}
+	}
+
+	page.lineWidth(1.5)
+	page.rect(left, top, cell*float64(p.Board.Width()), cell*float64(p.Board.Height()), false)
+}
+
+// pdfPage builds the content stream for a page, positions are measured from the top left corner of the page.
+type pdfPage struct {
+	content bytes.Buffer
+	height  float64
+}
+
+func newPDFPage(height float64) *pdfPage {
+	return &pdfPage{
+		bytes.Buffer{},
+		height,
+	}
+}
+
+// text draws text with its baseline at y.
+func (pg *pdfPage) text(font pdfFont, size float64, x float64, y float64, text []byte) {
+	fmt.Fprintf(&pg.content, "BT /%s %s Tf %s %s Td ", font.resource(), formatCoordinate(size), formatCoordinate(x), formatCoordinate(pg.height-y))
+	pg.content.Write(pdfString(text))
+	pg.content.WriteString(" Tj ET\n")
+}
+
+func (pg *pdfPage) lineWidth(width float64) {
+	fmt.Fprintf(&pg.content, "%s w\n", formatCoordinate(width))
+}
+
+// rect draws a rectangle with its top left corner at (x, y), either filled or outlined.
+func (pg *pdfPage) rect(x float64, y float64, width float64, height float64, fill bool) {
+	op := "S"
+	if fill {
+		op = "f"
+	}
+
+	fmt.Fprintf(&pg.content, "%s %s %s %s re %s\n", formatCoordinate(x), formatCoordinate(pg.height-y-height), formatCoordinate(width), formatCoordinate(height), op)
+}
+
+// circle outlines a circle centered on (x, y) using four bezier curves.
+func (pg *pdfPage) circle(x float64, y float64, r float64) {
+	const k = 0.5523 // control point distance for a quarter circle
+	cy := pg.height - y
+	c := r * k
+
+	fmt.Fprintf(&pg.content, "%s %s m\n", formatCoordinate(x+r), formatCoordinate(cy))
+	fmt.Fprintf(&pg.content, "%s %s %s %s %s %s c\n", formatCoordinate(x+r), formatCoordinate(cy+c), formatCoordinate(x+c), formatCoordinate(cy+r), formatCoordinate(x), formatCoordinate(cy+r))
+	fmt.Fprintf(&pg.content, "%s %s %s %s %s %s c\n", formatCoordinate(x-c), formatCoordinate(cy+r), formatCoordinate(x-r), formatCoordinate(cy+c), formatCoordinate(x-r), formatCoordinate(cy))
+	fmt.Fprintf(&pg.content, "%s %s %s %s %s %s c\n", formatCoordinate(x-r), formatCoordinate(cy-c), formatCoordinate(x-c), formatCoordinate(cy-r), formatCoordinate(x), formatCoordinate(cy-r))
+	fmt.Fprintf(&pg.content, "%s %s %s %s %s %s c S\n", formatCoordinate(x+c), formatCoordinate(cy-r), formatCoordinate(x+r), formatCoordinate(cy-c), formatCoordinate(x+r), formatCoordinate(cy))
+}
+
+// writePDF writes the document structure around the page content streams.
+func writePDF(p *Puzzle, pages []*pdfPage, width float64, height float64) []byte {
+	var out bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// catalog, page tree, info, and fonts come first so page objects start at 6
+	const firstPage = 6
+
+	var kids []string
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPage+i*2))
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object(fmt.Sprintf("<< /Title %s /Author %s /Producer (puz-parser) >>", pdfString(pdfText(p.textToUTF8(p.Title))), pdfString(pdfText(p.textToUTF8(p.Author)))))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, page := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents %d 0 R >>", formatCoordinate(width), formatCoordinate(height), firstPage+i*2+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.content.Len(), page.content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+// pdfString returns text as a PDF literal string, escaping delimiters and bytes outside of printable ASCII.
+func pdfString(text []byte) []byte {
+	out := []byte{'('}
+
+	for _, ch := range text {
+		switch {
+		case ch == '(' || ch == ')' || ch == '\\':
+			out = append(out, '\\', ch)
+		case ch < ' ' || ch > '~':
+			out = fmt.Appendf(out, "\\%03o", ch)
+		default:
+			out = append(out, ch)
+		}
+	}
+
+	return append(out, ')')
+}
+
+// pdfText converts UTF-8 text to WinAnsiEncoding, characters that can not be encoded become '?'.
+func pdfText(text string) []byte {
+	var out []byte
+
+	for _, r := range text {
+		switch {
+		case r == '\t' || r == '\r' || r == '\n':
+			out = append(out, ' ')
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiExtras[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+
+	return out
+}
+
+// pdfFont is one of the standard fonts every PDF reader provides
+type pdfFont int
+
+const (
+	pdfRegular pdfFont = iota
+	pdfBold
+)
+
+func (f pdfFont) resource() string {
+	if f == pdfBold {
+		return "F2"
+	}
+
+	return "F1"
+}
+
+// width returns the width of text in points at size.
+// Characters outside of printable ASCII use the width of a digit.
+func (f pdfFont) width(text []byte, size float64) float64 {
+	widths := &helveticaWidths
+	if f == pdfBold {
+		widths = &helveticaBoldWidths
+	}
+
+	total := 0
+
+	for _, ch := range text {
+		if ch >= ' ' && ch <= '~' {
+			total += widths[ch-' ']
+		} else {
+			total += 556
+		}
+	}
+
+	return float64(total) * size / 1000
+}
+
+// helveticaWidths are the Helvetica glyph widths for ' ' to '~' in thousandths of an em, from the Adobe font metrics
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// helveticaBoldWidths are the Helvetica-Bold glyph widths for ' ' to '~' in thousandths of an em, from the Adobe font metrics
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
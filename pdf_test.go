@@ -0,0 +1,158 @@
+package puz_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	puz "github.com/cqb13/puz-parser"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// checkPDFStructure verifies the header, trailer, and that every xref offset points at its object.
+func checkPDFStructure(t *testing.T, data []byte) {
+	t.Helper()
+
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatalf("Missing PDF header or trailer")
+	}
+
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if match == nil {
+		t.Fatalf("Failed to find startxref")
+	}
+
+	xref, _ := strconv.Atoi(string(match[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref does not point at the xref table")
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xref:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+
+		if !bytes.HasPrefix(data[offset:], fmt.Appendf(nil, "%d 0 obj\n", i+1)) {
+			t.Fatalf("xref entry %d does not point at its object", i+1)
+		}
+	}
+}
+
+func TestRenderPDF(t *testing.T) {
+	testCases := []string{
+		"Crossword.puz",
+		"NYT-Nov2193.puz",
+		"washpost.puz",
+		"Crossword-EXT-Rebus.puz",
+	}
+
+	for _, name := range testCases {
+		puzzle, err := puz.DecodePuz(loadFile(t, name))
+		if err != nil {
+			t.Fatalf("Failed to decode %s: %v", name, err)
+		}
+
+		data, err := puz.RenderPDF(puzzle, puz.PDFOptions{AnswerKey: true})
+		if err != nil {
+			t.Fatalf("Failed to render %s: %v", name, err)
+		}
+
+		checkPDFStructure(t, data)
+
+		if !bytes.Contains(data, []byte("/Count 2")) || !bytes.Contains(data, []byte("/MediaBox [0 0 612 792]")) {
+			t.Fatalf("Expected 2 letter pages for %s", name)
+		}
+
+		again, err := puz.RenderPDF(puzzle, puz.PDFOptions{AnswerKey: true})
+		if err != nil || !bytes.Equal(data, again) {
+			t.Fatalf("Expected the same output when rendering %s twice", name)
+		}
+	}
+}
+
+func TestRenderPDFContent(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	puzzle.Notes = "Hint (easy)"
+
+	data, err := puz.RenderPDF(puzzle, puz.PDFOptions{PageSize: puz.A4Page, Notes: true})
+	if err != nil {
+		t.Fatalf("Failed to render Crossword.puz: %v", err)
+	}
+
+	checkPDFStructure(t, data)
+
+	expected := []string{
+		"/Count 1",
+		"/MediaBox [0 0 595.28 841.89]",
+		"(ACROSS)",
+		"(DOWN)",
+		"(NOTES)",
+		"(Hint \\(easy\\))",
+	}
+
+	for _, clue := range puzzle.GetCluesByDirection(puz.Across) {
+		expected = append(expected, "("+strings.Fields(clue.Clue)[0])
+	}
+
+	for _, text := range expected {
+		if !bytes.Contains(data, []byte(text)) {
+			t.Fatalf("Expected the PDF to contain %s", text)
+		}
+	}
+
+	withoutNotes, err := puz.RenderPDF(puzzle, puz.PDFOptions{})
+	if err != nil {
+		t.Fatalf("Failed to render Crossword.puz: %v", err)
+	}
+
+	if bytes.Contains(withoutNotes, []byte("(NOTES)")) {
+		t.Fatalf("Expected notes to be left out by default")
+	}
+}
+
+func TestRenderPDFEmptyAnswer(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	puzzle.Board[1][1].Answer = puz.EmptySolutionSquare
+
+	data, err := puz.RenderPDF(puzzle, puz.PDFOptions{AnswerKey: true})
+	if err != nil {
+		t.Fatalf("Failed to render Crossword.puz: %v", err)
+	}
+
+	if bytes.Contains(data, []byte("( ) Tj")) {
+		t.Fatalf("Expected no text for a cell without an answer")
+	}
+}
+
+func TestRenderPDFErrors(t *testing.T) {
+	scrambled, err := puz.DecodePuz(loadFile(t, "Crossword-Scrambled.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword-Scrambled.puz: %v", err)
+	}
+
+	_, err = puz.RenderPDF(scrambled, puz.PDFOptions{AnswerKey: true})
+	if !errors.Is(err, puz.PuzzleIsScrambledError) {
+		t.Fatalf("Expected PuzzleIsScrambledError, found %v", err)
+	}
+
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	puzzle.Notes = strings.Repeat("A very long note that will not fit. ", 3000)
+
+	_, err = puz.RenderPDF(puzzle, puz.PDFOptions{Notes: true})
+	if !errors.Is(err, puz.PDFContentTooLargeError) {
+		t.Fatalf("Expected PDFContentTooLargeError, found %v", err)
+	}
+}
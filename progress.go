@@ -0,0 +1,86 @@
+package puz
+
+// Progress counts how much of a puzzle or word the player has solved
+type Progress struct {
+	Filled    int // Cells with a guess
+	Total     int // Cells that need a guess, solid squares are only counted in Diagramless puzzles
+	Correct   int // Filled cells with a correct guess
+	Incorrect int // Filled cells with a wrong guess
+}
+
+// IsFilled reports if every cell has a guess
+func (p Progress) IsFilled() bool {
+	return p.Filled == p.Total
+}
+
+// IsSolved reports if every cell has a correct guess
+func (p Progress) IsSolved() bool {
+	return p.Correct == p.Total
+}
+
+// Progress returns the solve progress for the whole board.
+//
+// Guesses are compared the same way as CheckPuzzle, including rebus values.
+// In a Diagramless puzzle the player also marks the solid squares, so a solid square is correct only when its guess is a solid square.
+// The answers of a scrambled puzzle are unknown, so only Filled and Total are counted.
+func (p *Puzzle) Progress() Progress {
+	return p.progress(p.Board.cells())
+}
+
+// WordProgress returns the solve progress for the cells in word.
+func (p *Puzzle) WordProgress(word Word) Progress {
+	return p.progress(p.wordCells(word))
+}
+
+// IsFilled reports if every cell on the board has a guess.
+func (p *Puzzle) IsFilled() bool {
+	return p.Progress().IsFilled()
+}
+
+// IsSolved reports if every cell on the board has a correct guess.
+// Scrambled puzzles are checked with CheckLockedSolution.
+func (p *Puzzle) IsSolved() bool {
+	if p.Scrambled() {
+		solved, _ := p.CheckLockedSolution()
+		return solved
+	}
+
+	return p.Progress().IsSolved()
+}
+
+func (p *Puzzle) progress(cells []Position) Progress {
+	var progress Progress
+
+	for _, pos := range cells {
+		solid := p.Board.IsSolidSquare(pos.X, pos.Y)
+		if solid && p.PuzzleType != Diagramless {
+			continue
+		}
+
+		progress.Total++
+
+		if p.isEmptyGuess(pos.X, pos.Y) {
+			continue
+		}
+
+		progress.Filled++
+
+		if p.Scrambled() {
+			continue
+		}
+
+		correct := p.guessIsCorrect(pos.X, pos.Y)
+		if solid {
+			guess := p.Board[pos.Y][pos.X].Guess
+			correct = guess == SolidSquare || guess == DiagramlessSolidSquare
+		}
+
+		if correct {
+			progress.Correct++
+		} else {
+			progress.Incorrect++
+		}
+	}
+
+	return progress
+}
@@ -0,0 +1,109 @@
+package puz_test
+
+import (
+	puz "github.com/cqb13/puz-parser"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	puzzle := loadUnsolved(t, "NYT-Nov2193.puz")
+
+	progress := puzzle.Progress()
+	if progress.Filled != 0 || progress.Total == 0 || progress.IsFilled() || puzzle.IsSolved() {
+		t.Fatalf("Expected an empty puzzle, found %+v", progress)
+	}
+
+	word := puzzle.Board.GetWords()[0]
+	puzzle.RevealWord(word)
+
+	wordProgress := puzzle.WordProgress(word)
+	if !wordProgress.IsSolved() || wordProgress.Total != len(word.Word) {
+		t.Fatalf("Expected the revealed word to be solved, found %+v", wordProgress)
+	}
+
+	progress = puzzle.Progress()
+	if progress.Filled != len(word.Word) || progress.Correct != len(word.Word) || progress.Incorrect != 0 {
+		t.Fatalf("Expected only the word to be filled, found %+v", progress)
+	}
+
+	puzzle.Board[word.StartY][word.StartX].Guess = puz.SolidSquare
+
+	progress = puzzle.Progress()
+	if progress.Incorrect != 1 {
+		t.Fatalf("Expected a solid guess in a letter cell to be incorrect, found %+v", progress)
+	}
+
+	puzzle.RevealPuzzle()
+
+	if !puzzle.IsFilled() || !puzzle.IsSolved() {
+		t.Fatalf("Expected a revealed puzzle to be solved, found %+v", puzzle.Progress())
+	}
+}
+
+func TestProgressRebus(t *testing.T) {
+	puzzle := loadUnsolved(t, "Crossword.puz")
+	puzzle.SetRebus(0, 0, "HEART")
+	puzzle.RevealPuzzle()
+
+	puzzle.SetUserRebus(0, 0, "HEARD")
+
+	if puzzle.IsSolved() {
+		t.Fatalf("Expected a wrong rebus guess to be incorrect")
+	}
+
+	puzzle.SetUserRebus(0, 0, "heart")
+
+	if !puzzle.IsSolved() {
+		t.Fatalf("Expected the rebus guess to be correct, found %+v", puzzle.Progress())
+	}
+}
+
+func TestProgressDiagramless(t *testing.T) {
+	puzzle := loadUnsolved(t, "NYT-Diagramless.puz")
+
+	if err := puzzle.Unscramble(3285); err != nil {
+		t.Fatalf("Failed to unscramble NYT-Diagramless.puz: %v", err)
+	}
+
+	progress := puzzle.Progress()
+	if progress.Total != puzzle.Board.Width()*puzzle.Board.Height() || puzzle.IsSolved() {
+		t.Fatalf("Expected diagramless solid squares to need a guess, found %+v", progress)
+	}
+
+	puzzle.RevealPuzzle()
+
+	if !puzzle.IsSolved() {
+		t.Fatalf("Expected a revealed diagramless puzzle to be solved, found %+v", puzzle.Progress())
+	}
+
+	for _, word := range puzzle.Board.GetWords() {
+		if word.Direction != puz.Across || word.StartX == 0 {
+			continue
+		}
+
+		// the square before an across word is solid
+		puzzle.Board[word.StartY][word.StartX-1].Guess = 'A'
+		break
+	}
+
+	progress = puzzle.Progress()
+	if progress.Incorrect != 1 || puzzle.IsSolved() {
+		t.Fatalf("Expected a letter in a solid square to be incorrect, found %+v", progress)
+	}
+}
+
+func TestProgressScrambled(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "NYT-Locked.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode NYT-Locked.puz: %v", err)
+	}
+
+	progress := puzzle.Progress()
+	if progress.Correct != 0 || progress.Incorrect != 0 || progress.Total == 0 {
+		t.Fatalf("Expected only totals for a scrambled puzzle, found %+v", progress)
+	}
+
+	if puzzle.IsSolved() {
+		t.Fatalf("Expected an empty scrambled puzzle to not be solved")
+	}
+}
@@ -1,6 +1,7 @@
 package puz
 
 import (
+	"context"
 	"fmt"
 	"slices"
 )
@@ -61,6 +62,21 @@ func (s ExtraSection) String() string {
 	return sectionStrMap[s]
 }
 
+// validSectionName returns true if name is 4 uppercase letters or digits
+func validSectionName(name []byte) bool {
+	if len(name) != 4 {
+		return false
+	}
+
+	for _, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
 // MarkupSquare represents formatting that can be added to a Cell
 type MarkupSquare byte
 
@@ -93,7 +109,8 @@ func NewPuzzle(width uint8, height uint8) *Puzzle {
 }
 
 // NewPuzzleFromBoard creates a new puzzle with the given board
-// If RebusKey or Markup values were changed in the board the corresponding extra sections should added with AddExtraSection()
+// If RebusKey values were changed in the board the rebus sections should be added with AddExtraSection() or set with SetRebus()
+// The markup section is added when encoding if any cell has markup
 func NewPuzzleFromBoard(board Board) *Puzzle {
 	return &Puzzle{
 		"",
@@ -105,7 +122,8 @@ func NewPuzzleFromBoard(board Board) *Puzzle {
 		0,
 		make([]Clue, 0),
 		extraSections{
-			make([]ExtraSection, 0),
+			make([]string, 0),
+			make([]RawExtraSection, 0),
 			make([]RebusEntry, 0),
 			TimerData{
 				0,
@@ -160,10 +178,11 @@ func (p *Puzzle) SetClues(clues Clues) {
 }
 
 // GetClueByPos searches for a clue with matching x, y (indices on the game board) coordinates and word direction
+// The returned clue points into the puzzle, so changes to it are kept
 func (p *Puzzle) GetClueByPos(x int, y int, dir Direction) (*Clue, bool) {
-	for _, clue := range p.clues {
-		if clue.Direction == dir && clue.StartX == x && clue.StartY == y {
-			return &clue, true
+	for i := range p.clues {
+		if p.clues[i].Direction == dir && p.clues[i].StartX == x && p.clues[i].StartY == y {
+			return &p.clues[i], true
 		}
 	}
 
@@ -171,10 +190,11 @@ func (p *Puzzle) GetClueByPos(x int, y int, dir Direction) (*Clue, bool) {
 }
 
 // GetClueByNum searches for a clue with matching clue number and word direction
+// The returned clue points into the puzzle, so changes to it are kept
 func (p *Puzzle) GetClueByNum(num int, dir Direction) (*Clue, bool) {
-	for _, clue := range p.clues {
-		if clue.Direction == dir && clue.Num == num {
-			return &clue, true
+	for i := range p.clues {
+		if p.clues[i].Direction == dir && p.clues[i].Num == num {
+			return &p.clues[i], true
 		}
 	}
 
@@ -254,27 +274,100 @@ func (p *Puzzle) AddExtraSection(section ExtraSection) bool {
 		return false
 	}
 
-	p.Extras.extraSectionOrder = append(p.Extras.extraSectionOrder, section)
+	p.Extras.extraSectionOrder = append(p.Extras.extraSectionOrder, section.String())
 
 	return true
 }
 
-// AddExtraSection appends the given section to the list of included extra sections if it isn't already included.
+// RemoveExtraSection removes the given section from the list of included extra sections.
 func (p *Puzzle) RemoveExtraSection(section ExtraSection) bool {
-	index := slices.Index(p.Extras.extraSectionOrder, section)
+	return p.removeSectionName(section.String())
+}
+
+// HasExtraSection returns true if the given section is in the list of extra sections
+func (p *Puzzle) HasExtraSection(section ExtraSection) bool {
+	return slices.Contains(p.Extras.extraSectionOrder, section.String())
+}
+
+// AddRawExtraSection appends a section that is not parsed by this package, it is written after the current extra sections.
+// The name must be 4 uppercase letters or digits and can not be the name of a known section.
+func (p *Puzzle) AddRawExtraSection(section RawExtraSection) error {
+	if !validSectionName([]byte(section.Name)) {
+		return InvalidExtraSectionNameError
+	}
+
+	if _, ok := getSectionFromString(section.Name); ok {
+		return InvalidExtraSectionNameError
+	}
+
+	if len(section.Data) > 0xFFFF {
+		return ExtraSectionTooLargeError
+	}
+
+	if slices.Contains(p.Extras.extraSectionOrder, section.Name) {
+		return &DuplicateExtraSectionError{
+			section.Name,
+		}
+	}
+
+	p.Extras.extraSectionOrder = append(p.Extras.extraSectionOrder, section.Name)
+	p.Extras.rawSections = append(p.Extras.rawSections, RawExtraSection{
+		section.Name,
+		slices.Clone(section.Data),
+	})
+
+	return nil
+}
+
+// RemoveRawExtraSection removes the raw section with the given name
+func (p *Puzzle) RemoveRawExtraSection(name string) bool {
+	index := slices.IndexFunc(p.Extras.rawSections, func(section RawExtraSection) bool {
+		return section.Name == name
+	})
 
 	if index == -1 {
 		return false
 	}
 
-	p.Extras.extraSectionOrder = append(p.Extras.extraSectionOrder[:index], p.Extras.extraSectionOrder[index+1:]...)
+	p.Extras.rawSections = slices.Delete(p.Extras.rawSections, index, index+1)
 
-	return true
+	return p.removeSectionName(name)
 }
 
-// HasExtraSection returns true if the given section is in the list of extra sections
-func (p *Puzzle) HasExtraSection(section ExtraSection) bool {
-	return slices.Contains(p.Extras.extraSectionOrder, section)
+// GetRawExtraSection returns the raw section with the given name
+func (p *Puzzle) GetRawExtraSection(name string) (RawExtraSection, bool) {
+	for _, section := range p.Extras.rawSections {
+		if section.Name == name {
+			return section, true
+		}
+	}
+
+	return RawExtraSection{}, false
+}
+
+// RawExtraSections returns all raw sections in the order they will be written
+func (p *Puzzle) RawExtraSections() []RawExtraSection {
+	var sections []RawExtraSection
+
+	for _, name := range p.Extras.extraSectionOrder {
+		if section, ok := p.GetRawExtraSection(name); ok {
+			sections = append(sections, section)
+		}
+	}
+
+	return sections
+}
+
+func (p *Puzzle) removeSectionName(name string) bool {
+	index := slices.Index(p.Extras.extraSectionOrder, name)
+
+	if index == -1 {
+		return false
+	}
+
+	p.Extras.extraSectionOrder = append(p.Extras.extraSectionOrder[:index], p.Extras.extraSectionOrder[index+1:]...)
+
+	return true
 }
 
 /*
@@ -285,10 +378,21 @@ Sorts extra sections to comply with standard order
 3. TimerSection           LTIM
 4. MarkupBoardSection     GEXT
 5. UserRebusTableSection  RUSR
+
+Raw sections are kept in their current order after the known sections.
 */
 func (p *Puzzle) SortExtraSections() {
-	slices.SortFunc(p.Extras.extraSectionOrder, func(a ExtraSection, b ExtraSection) int {
-		return int(a) - int(b)
+	rank := func(name string) int {
+		section, ok := getSectionFromString(name)
+		if !ok {
+			return len(sectionMap)
+		}
+
+		return int(section)
+	}
+
+	slices.SortStableFunc(p.Extras.extraSectionOrder, func(a string, b string) int {
+		return rank(a) - rank(b)
 	})
 }
 
@@ -299,7 +403,8 @@ func (p *Puzzle) Scrambled() bool {
 // Unscramble attempts to unscramble the puzzle using the key.
 // A valid key is made of 4 non zero digits.
 // Unscrambling will fail if the board is already unscrambled, an invalid key is provided,
-// the key is incorrect, the board has non ASCII letters (a-z / A-Z), or the board has less than 12 valid letters.
+// the key is incorrect, the board has lowercase, blank, or non ASCII answers, or the board has less than 12 uppercase letters.
+// Digits, symbols, and solid squares are left in place.
 func (p *Puzzle) Unscramble(key int) error {
 	if !p.Scrambled() {
 		return PuzzleIsUnscrambledError
@@ -313,10 +418,43 @@ func (p *Puzzle) Unscramble(key int) error {
 	return nil
 }
 
+// RecoverScrambleKey finds the key for a scrambled puzzle by trying every valid key in parallel.
+// The puzzle is not modified, pass the key to Unscramble to unscramble it.
+// Recovery fails if the puzzle is not scrambled, ctx is cancelled before a matching key is found, or no key matches the scrambled checksum.
+func (p *Puzzle) RecoverScrambleKey(ctx context.Context) (int, error) {
+	if !p.Scrambled() {
+		return 0, PuzzleIsUnscrambledError
+	}
+
+	key, err := recoverScrambleKey(ctx, p)
+	if err != nil {
+		return 0, fmt.Errorf("Failed to recover scramble key: %w", err)
+	}
+
+	return key, nil
+}
+
+// CheckLockedSolution reports if the players guesses solve a scrambled puzzle without needing the key.
+// The guesses are checked against the checksum of the unscrambled solution, so an incomplete grid is never correct.
+func (p *Puzzle) CheckLockedSolution() (bool, error) {
+	if !p.Scrambled() {
+		return false, PuzzleIsUnscrambledError
+	}
+
+	guesses, complete := createGuessScramble(p)
+	if !complete {
+		return false, nil
+	}
+
+	return checksumRegion([]byte(guesses), 0) == p.scramble.scrambledChecksum, nil
+}
+
 // Scramble attempts to scramble the puzzle using the key.
 // A valid key is made of 4 non zero digits.
 // Scrambling will fail if the board is already scrambled, an invalid key is provided,
-// the board has non ASCII letters (a-z / A-Z), or the board has less than 12 valid letters.
+// the board has lowercase, blank, or non ASCII answers, or the board has less than 12 uppercase letters.
+// Digits, symbols, and solid squares are left in place, a rebus is scrambled by its first letter only.
+// The cells that prevent scrambling can be found with errors.As and UnscramblableCellsError.
 func (p *Puzzle) Scramble(key int) error {
 	if p.Scrambled() {
 		return PuzzleIsScrambledError
@@ -371,13 +509,20 @@ func NewClue(clue string, num int, x int, y int, dir Direction) Clue {
 }
 
 type extraSections struct {
-	extraSectionOrder []ExtraSection // The order to write extra sections when encoding
-	RebusTable        []RebusEntry   // The rebus table
-	Timer             TimerData      // The state of the timer
-	UserRebusTable    []RebusEntry   // The rebus table guessed by the player
+	extraSectionOrder []string          // The names of extra sections in the order to write them when encoding
+	rawSections       []RawExtraSection // Sections that are not parsed by this package
+	RebusTable        []RebusEntry      // The rebus table
+	Timer             TimerData         // The state of the timer
+	UserRebusTable    []RebusEntry      // The rebus table guessed by the player
+}
+
+// RawExtraSection is an extra section that is not parsed by this package, its data is preserved as is
+type RawExtraSection struct {
+	Name string // The 4 character section name
+	Data []byte // The section data, without the null terminator
 }
 
-// TODO: add methods to add rebus entries to board
+// RebusEntry links a rebus value to cells, use Puzzle.SetRebus and Puzzle.Rebus to manage entries
 type RebusEntry struct {
 	Key   int    // Key links the entry to a cell on the board
 	Value string // The value
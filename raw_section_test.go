@@ -0,0 +1,153 @@
+package puz_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	puz "github.com/cqb13/puz-parser"
+	"slices"
+	"testing"
+)
+
+// buildSection encodes a section with a correct checksum
+func buildSection(name string, data []byte) []byte {
+	var checksum uint16
+	for _, b := range data {
+		checksum = (checksum >> 1) | ((checksum & 1) << 15)
+		checksum += uint16(b)
+	}
+
+	section := []byte(name)
+	section = binary.LittleEndian.AppendUint16(section, uint16(len(data)))
+	section = binary.LittleEndian.AppendUint16(section, checksum)
+	section = append(section, data...)
+
+	return append(section, 0x00)
+}
+
+// insertBeforeSection inserts raw bytes in front of the named section
+func insertBeforeSection(t *testing.T, data []byte, name string, insert []byte) []byte {
+	index := bytes.Index(data, []byte(name))
+	if index == -1 {
+		t.Fatalf("Failed to find %s section", name)
+	}
+
+	return slices.Concat(data[:index], insert, data[index:])
+}
+
+func TestRawExtraSectionBeforeKnownSection(t *testing.T) {
+	data := insertBeforeSection(t, loadFile(t, "Crossword-EXT-Rebus.puz"), "GRBS", buildSection("XTRA", []byte("vendor data")))
+
+	puzzle, err := puz.DecodePuz(data)
+	if err != nil {
+		t.Fatalf("Failed to decode puzzle with a raw section: %v", err)
+	}
+
+	raw, ok := puzzle.GetRawExtraSection("XTRA")
+	if !ok || string(raw.Data) != "vendor data" {
+		t.Fatalf("Failed to preserve the raw section, found %v", raw)
+	}
+
+	if !puzzle.HasExtraSection(puz.RebusSection) || !puzzle.HasExtraSection(puz.RebusTableSection) {
+		t.Fatalf("Failed to parse known sections after the raw section")
+	}
+
+	if bytes.Contains(puzzle.UnusedData.Postscript, []byte("RTBL")) {
+		t.Fatalf("Known sections were left in the postscript")
+	}
+
+	encoded, err := puz.EncodePuz(puzzle)
+	if err != nil {
+		t.Fatalf("Failed to encode puzzle with a raw section: %v", err)
+	}
+
+	if !bytes.Equal(data, encoded) {
+		t.Errorf("Encoded bytes do not match original\n\noriginal:\n%s\n\nnew:\n%s", buildHex(data), buildHex(encoded))
+	}
+}
+
+func TestRawExtraSectionChecksum(t *testing.T) {
+	section := buildSection("XTRA", []byte("vendor data"))
+	section[6] ^= 0xFF
+
+	data := insertBeforeSection(t, loadFile(t, "Crossword-EXT-Rebus.puz"), "GRBS", section)
+
+	_, err := puz.DecodePuz(data)
+
+	var sectionErr *puz.ExtraSectionChecksumMismatchError
+	if !errors.As(err, &sectionErr) {
+		t.Fatalf("Expected ExtraSectionChecksumMismatchError, found %v", err)
+	}
+
+	_, mismatches, err := puz.DecodePuzWithOptions(data, puz.DecodeOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("Failed to decode leniently: %v", err)
+	}
+
+	if len(mismatches) != 1 || mismatches[0].Section != "XTRA" {
+		t.Fatalf("Expected an XTRA mismatch, found %v", mismatches)
+	}
+}
+
+func TestAddRawExtraSection(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	err = puzzle.AddRawExtraSection(puz.RawExtraSection{Name: "XTRA", Data: []byte{1, 2, 3}})
+	if err != nil {
+		t.Fatalf("Failed to add raw section: %v", err)
+	}
+
+	var duplicateErr *puz.DuplicateExtraSectionError
+	err = puzzle.AddRawExtraSection(puz.RawExtraSection{Name: "XTRA"})
+	if !errors.As(err, &duplicateErr) {
+		t.Fatalf("Expected DuplicateExtraSectionError, found %v", err)
+	}
+
+	for _, name := range []string{"GEXT", "xtra", "TOOLONG"} {
+		err = puzzle.AddRawExtraSection(puz.RawExtraSection{Name: name})
+		if err != puz.InvalidExtraSectionNameError {
+			t.Errorf("Expected InvalidExtraSectionNameError for %s, found %v", name, err)
+		}
+	}
+
+	puzzle.Board[0][0].Markup = puz.SquareCircled
+	puzzle.AddExtraSection(puz.MarkupBoardSection)
+	puzzle.SortExtraSections()
+
+	encoded, err := puz.EncodePuz(puzzle)
+	if err != nil {
+		t.Fatalf("Failed to encode puzzle: %v", err)
+	}
+
+	if bytes.Index(encoded, []byte("GEXT")) > bytes.Index(encoded, []byte("XTRA")) {
+		t.Errorf("Expected known sections to be sorted before raw sections")
+	}
+
+	decoded, err := puz.DecodePuz(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode puzzle: %v", err)
+	}
+
+	if len(decoded.RawExtraSections()) != 1 {
+		t.Fatalf("Expected 1 raw section, found %d", len(decoded.RawExtraSections()))
+	}
+
+	if raw, _ := decoded.GetRawExtraSection("XTRA"); !bytes.Equal(raw.Data, []byte{1, 2, 3}) {
+		t.Fatalf("Raw section data changed during round trip, found %v", raw.Data)
+	}
+
+	if decoded.Board[0][0].Markup != puz.SquareCircled {
+		t.Fatalf("Failed to keep markup after the raw section")
+	}
+
+	if !decoded.RemoveRawExtraSection("XTRA") {
+		t.Fatalf("Failed to remove raw section")
+	}
+
+	if _, ok := decoded.GetRawExtraSection("XTRA"); ok {
+		t.Fatalf("Found removed raw section")
+	}
+}
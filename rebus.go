@@ -0,0 +1,310 @@
+package puz
+
+import (
+	"slices"
+	"strings"
+)
+
+const maxRebusKey = 255 // RebusKey is stored as a single byte in the GRBS board
+
+// rebusEntryByKey returns the entry in table linked to key.
+func rebusEntryByKey(table []RebusEntry, key int) (RebusEntry, bool) {
+	for _, entry := range table {
+		if entry.Key == key {
+			return entry, true
+		}
+	}
+
+	return RebusEntry{}, false
+}
+
+// nextRebusKey returns the smallest key that is not used in table.
+// Returns RebusTableFullError if every key that fits in a cell is already used.
+func nextRebusKey(table []RebusEntry) (int, error) {
+	for key := 1; key <= maxRebusKey; key++ {
+		used := slices.ContainsFunc(table, func(entry RebusEntry) bool {
+			return entry.Key == key
+		})
+
+		if !used {
+			return key, nil
+		}
+	}
+
+	return 0, RebusTableFullError
+}
+
+// SetRebus sets the answer for the cell at (x, y) to value.
+// The key of an existing entry with the same value is reused, otherwise a new entry is added to the rebus table.
+// The GRBS and RTBL sections are added if needed.
+func (p *Puzzle) SetRebus(x int, y int, value string) error {
+	if !p.Board.inBounds(x, y) {
+		return OutOfBoundsWriteError
+	}
+
+	if value == "" {
+		return EmptyRebusValueError
+	}
+
+	key := 0
+
+	for _, entry := range p.Extras.RebusTable {
+		if entry.Value == value {
+			key = entry.Key
+			break
+		}
+	}
+
+	if key == 0 {
+		newKey, err := p.addRebusEntry(value)
+		if err != nil {
+			return err
+		}
+
+		key = newKey
+	}
+
+	p.Board[y][x].Answer = value[0]
+	p.Board[y][x].RebusKey = byte(key)
+	p.AddExtraSection(RebusSection)
+	p.AddExtraSection(RebusTableSection)
+
+	return nil
+}
+
+// addRebusEntry adds value to the rebus table under the smallest unused key and returns the key.
+func (p *Puzzle) addRebusEntry(value string) (int, error) {
+	key, err := nextRebusKey(p.liveRebusTable())
+	if err != nil {
+		return 0, err
+	}
+
+	// the key may belong to entries that are no longer used by any cell
+	unused := func(entry RebusEntry) bool {
+		return entry.Key == key
+	}
+	p.Extras.RebusTable = slices.DeleteFunc(p.Extras.RebusTable, unused)
+	p.Extras.UserRebusTable = slices.DeleteFunc(p.Extras.UserRebusTable, unused)
+	p.Extras.RebusTable = append(p.Extras.RebusTable, RebusEntry{key, value})
+
+	return key, nil
+}
+
+// ClearRebus removes the rebus from the cell at (x, y), the first letter of the rebus is kept as the answer.
+// The rebus sections are removed once no cell contains a rebus.
+func (p *Puzzle) ClearRebus(x int, y int) error {
+	if !p.Board.inBounds(x, y) {
+		return OutOfBoundsWriteError
+	}
+
+	if p.Board[y][x].RebusKey == 0 {
+		return NotARebusCellError
+	}
+
+	p.Board[y][x].RebusKey = 0
+
+	if !p.Board.HasRebus() {
+		p.RemoveExtraSection(RebusSection)
+		p.RemoveExtraSection(RebusTableSection)
+		p.RemoveExtraSection(UserRebusTableSection)
+		p.Extras.RebusTable = nil
+		p.Extras.UserRebusTable = nil
+	}
+
+	return nil
+}
+
+// Rebus returns the rebus value for the cell at (x, y).
+// The bool is false if the cell does not contain a rebus.
+func (p *Puzzle) Rebus(x int, y int) (string, bool) {
+	if !p.Board.IsRebus(x, y) {
+		return "", false
+	}
+
+	entry, ok := rebusEntryByKey(p.Extras.RebusTable, int(p.Board[y][x].RebusKey))
+
+	return entry.Value, ok
+}
+
+// UserRebus returns the players rebus guess for the cell at (x, y).
+// The bool is false if the player has not entered a rebus guess,
+// or if the guess letter for the cell was changed and no longer starts the rebus guess.
+func (p *Puzzle) UserRebus(x int, y int) (string, bool) {
+	if !p.Board.IsRebus(x, y) {
+		return "", false
+	}
+
+	entry, ok := rebusEntryByKey(p.Extras.UserRebusTable, int(p.Board[y][x].RebusKey))
+	if !ok || entry.Value == "" || !strings.EqualFold(entry.Value[:1], string([]byte{p.Board[y][x].Guess})) {
+		return "", false
+	}
+
+	return entry.Value, true
+}
+
+// SetUserRebus sets the players guess for the rebus cell at (x, y) to value and adds the RUSR section if needed.
+// User rebus entries share keys with the rebus table, so the cell must already be a rebus.
+// A cell that shares its key with other cells is moved to a key of its own with a copy of its rebus table entry,
+// so a guess never changes the guesses of the other cells.
+// Returns RebusTableFullError if the cell needs a new key and every key is used.
+func (p *Puzzle) SetUserRebus(x int, y int, value string) error {
+	if !p.Board.inBounds(x, y) {
+		return OutOfBoundsWriteError
+	}
+
+	if value == "" {
+		return EmptyRebusValueError
+	}
+
+	key := int(p.Board[y][x].RebusKey)
+	if key == 0 {
+		return NotARebusCellError
+	}
+
+	if entry, ok := rebusEntryByKey(p.Extras.UserRebusTable, key); (!ok || entry.Value != value) && p.sharesRebusKey(x, y) {
+		newKey, err := p.userRebusKey(key, value)
+		if err != nil {
+			return err
+		}
+
+		key = newKey
+		p.Board[y][x].RebusKey = byte(key)
+	}
+
+	p.Board[y][x].Guess = value[0]
+
+	index := slices.IndexFunc(p.Extras.UserRebusTable, func(entry RebusEntry) bool {
+		return entry.Key == key
+	})
+
+	if index == -1 {
+		p.Extras.UserRebusTable = append(p.Extras.UserRebusTable, RebusEntry{key, value})
+	} else {
+		p.Extras.UserRebusTable[index].Value = value
+	}
+
+	p.AddExtraSection(UserRebusTableSection)
+
+	return nil
+}
+
+// sharesRebusKey returns true if another cell on the board uses the same rebus key as the cell at (x, y).
+func (p *Puzzle) sharesRebusKey(x int, y int) bool {
+	key := p.Board[y][x].RebusKey
+
+	for _, pos := range p.Board.cells() {
+		if pos != (Position{x, y}) && p.Board[pos.Y][pos.X].RebusKey == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// userRebusKey returns a key with the same rebus value as key whose user rebus entry is guess.
+// A new key is added to the rebus table if no such key exists.
+func (p *Puzzle) userRebusKey(key int, guess string) (int, error) {
+	answer, ok := rebusEntryByKey(p.Extras.RebusTable, key)
+	if !ok {
+		return key, nil
+	}
+
+	for _, entry := range p.liveRebusTable() {
+		if entry.Key == key || entry.Value != answer.Value {
+			continue
+		}
+
+		if user, ok := rebusEntryByKey(p.Extras.UserRebusTable, entry.Key); ok && user.Value == guess {
+			return entry.Key, nil
+		}
+	}
+
+	return p.addRebusEntry(answer.Value)
+}
+
+// Answer returns the answer for the cell at (x, y) with a rebus expanded to its full value.
+// Returns an empty string if (x, y) is outside of the board.
+func (p *Puzzle) Answer(x int, y int) string {
+	if !p.Board.inBounds(x, y) {
+		return ""
+	}
+
+	if value, ok := p.Rebus(x, y); ok {
+		return value
+	}
+
+	return string([]byte{p.Board[y][x].Answer})
+}
+
+// Guess returns the players guess for the cell at (x, y) with a rebus guess expanded to its full value.
+// Returns an empty string if (x, y) is outside of the board.
+func (p *Puzzle) Guess(x int, y int) string {
+	if !p.Board.inBounds(x, y) {
+		return ""
+	}
+
+	if value, ok := p.UserRebus(x, y); ok {
+		return value
+	}
+
+	return string([]byte{p.Board[y][x].Guess})
+}
+
+// WordAnswer returns the answer for word with rebus cells expanded.
+func (p *Puzzle) WordAnswer(word Word) string {
+	return p.expandWord(word, p.Answer)
+}
+
+// WordGuess returns the players guess for word with rebus guesses expanded.
+func (p *Puzzle) WordGuess(word Word) string {
+	return p.expandWord(word, p.Guess)
+}
+
+// GetWords returns a list of Words from the board with rebus cells expanded to their full values.
+func (p *Puzzle) GetWords() []Word {
+	words := p.Board.GetWords()
+
+	for i := range words {
+		words[i].Word = p.WordAnswer(words[i])
+	}
+
+	return words
+}
+
+func (p *Puzzle) expandWord(word Word, cell func(x int, y int) string) string {
+	var out strings.Builder
+
+	for _, pos := range p.wordCells(word) {
+		out.WriteString(cell(pos.X, pos.Y))
+	}
+
+	return out.String()
+}
+
+// liveRebusTable returns the entries in the rebus table that are used by a cell
+func (p *Puzzle) liveRebusTable() []RebusEntry {
+	return usedRebusEntries(p.Board, p.Extras.RebusTable)
+}
+
+// usedRebusEntries returns the entries of table that are referenced by a cell on the board
+func usedRebusEntries(board Board, table []RebusEntry) []RebusEntry {
+	used := make(map[int]bool)
+
+	for y := range board {
+		for x := range board[y] {
+			if board[y][x].RebusKey != 0 {
+				used[int(board[y][x].RebusKey)] = true
+			}
+		}
+	}
+
+	var entries []RebusEntry
+
+	for _, entry := range table {
+		if used[entry.Key] {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
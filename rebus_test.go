@@ -0,0 +1,212 @@
+package puz_test
+
+import (
+	puz "github.com/cqb13/puz-parser"
+	"testing"
+)
+
+func TestSetRebus(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	if err := puzzle.SetRebus(0, 0, "HEART"); err != nil {
+		t.Fatalf("Failed to set rebus: %v", err)
+	}
+
+	if err := puzzle.SetRebus(1, 0, "HEART"); err != nil {
+		t.Fatalf("Failed to set rebus: %v", err)
+	}
+
+	if err := puzzle.SetRebus(2, 0, "STAR"); err != nil {
+		t.Fatalf("Failed to set rebus: %v", err)
+	}
+
+	if puzzle.Board[0][0].RebusKey != puzzle.Board[0][1].RebusKey {
+		t.Errorf("Expected equal values to share a key")
+	}
+
+	if puzzle.Board[0][0].RebusKey == puzzle.Board[0][2].RebusKey {
+		t.Errorf("Expected different values to use different keys")
+	}
+
+	if value, ok := puzzle.Rebus(2, 0); !ok || value != "STAR" {
+		t.Errorf("Expected STAR, found %q", value)
+	}
+
+	if _, ok := puzzle.Rebus(3, 0); ok {
+		t.Errorf("Found a rebus in a normal cell")
+	}
+
+	if !puzzle.HasExtraSection(puz.RebusSection) || !puzzle.HasExtraSection(puz.RebusTableSection) {
+		t.Fatalf("Failed to add the rebus sections")
+	}
+
+	if err := puzzle.SetRebus(0, 0, ""); err != puz.EmptyRebusValueError {
+		t.Errorf("Expected EmptyRebusValueError, found %v", err)
+	}
+
+	if err := puzzle.SetRebus(-1, 0, "HEART"); err != puz.OutOfBoundsWriteError {
+		t.Errorf("Expected OutOfBoundsWriteError, found %v", err)
+	}
+
+	encoded, err := puz.EncodePuz(puzzle)
+	if err != nil {
+		t.Fatalf("Failed to encode puzzle: %v", err)
+	}
+
+	decoded, err := puz.DecodePuz(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode puzzle: %v", err)
+	}
+
+	for x, expected := range []string{"HEART", "HEART", "STAR"} {
+		if value, ok := decoded.Rebus(x, 0); !ok || value != expected {
+			t.Errorf("Expected %s at x: %d, found %q", expected, x, value)
+		}
+	}
+}
+
+func TestClearRebus(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	puzzle.SetRebus(0, 0, "HEART")
+	puzzle.SetRebus(1, 0, "STAR")
+
+	if err := puzzle.ClearRebus(1, 0); err != nil {
+		t.Fatalf("Failed to clear rebus: %v", err)
+	}
+
+	if puzzle.Board[0][1].Answer != 'S' {
+		t.Errorf("Expected the first letter to be kept, found %c", puzzle.Board[0][1].Answer)
+	}
+
+	// the unused STAR entry is left in the table but not encoded
+	encoded, err := puz.EncodePuz(puzzle)
+	if err != nil {
+		t.Fatalf("Failed to encode puzzle: %v", err)
+	}
+
+	if len(puzzle.Extras.RebusTable) != 2 {
+		t.Errorf("Encoding should not modify the rebus table")
+	}
+
+	decoded, err := puz.DecodePuz(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode puzzle: %v", err)
+	}
+
+	if len(decoded.Extras.RebusTable) != 1 || decoded.Extras.RebusTable[0].Value != "HEART" {
+		t.Errorf("Expected unused entries to be dropped, found %v", decoded.Extras.RebusTable)
+	}
+
+	// a new value can reuse the key of the unused entry
+	puzzle.SetRebus(2, 0, "MOON")
+	if len(puzzle.Extras.RebusTable) != 2 {
+		t.Errorf("Expected the unused key to be reused, found %v", puzzle.Extras.RebusTable)
+	}
+
+	puzzle.ClearRebus(0, 0)
+	puzzle.ClearRebus(2, 0)
+
+	if puzzle.HasExtraSection(puz.RebusSection) || puzzle.HasExtraSection(puz.RebusTableSection) {
+		t.Errorf("Expected rebus sections to be removed")
+	}
+
+	if err := puzzle.ClearRebus(0, 0); err != puz.NotARebusCellError {
+		t.Errorf("Expected NotARebusCellError, found %v", err)
+	}
+}
+
+func TestSetUserRebus(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	if err := puzzle.SetUserRebus(0, 0, "HEART"); err != puz.NotARebusCellError {
+		t.Fatalf("Expected NotARebusCellError, found %v", err)
+	}
+
+	puzzle.SetRebus(0, 0, "HEART")
+
+	if err := puzzle.SetUserRebus(0, 0, "HEAT"); err != nil {
+		t.Fatalf("Failed to set user rebus: %v", err)
+	}
+
+	if err := puzzle.SetUserRebus(0, 0, "HEARD"); err != nil {
+		t.Fatalf("Failed to set user rebus: %v", err)
+	}
+
+	if !puzzle.HasExtraSection(puz.UserRebusTableSection) {
+		t.Fatalf("Failed to add the RUSR section")
+	}
+
+	encoded, err := puz.EncodePuz(puzzle)
+	if err != nil {
+		t.Fatalf("Failed to encode puzzle: %v", err)
+	}
+
+	decoded, err := puz.DecodePuz(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode puzzle: %v", err)
+	}
+
+	if value, ok := decoded.UserRebus(0, 0); !ok || value != "HEARD" {
+		t.Errorf("Expected HEARD, found %q", value)
+	}
+
+	if decoded.Board[0][0].Guess != 'H' {
+		t.Errorf("Expected guess H, found %c", decoded.Board[0][0].Guess)
+	}
+}
+
+func TestSetUserRebusSharedKey(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	puzzle.SetRebus(0, 0, "HEART")
+	puzzle.SetRebus(1, 1, "HEART")
+
+	if puzzle.Board[0][0].RebusKey != puzzle.Board[1][1].RebusKey {
+		t.Fatalf("Expected both cells to share a rebus key")
+	}
+
+	if err := puzzle.SetUserRebus(0, 0, "HEARD"); err != nil {
+		t.Fatalf("Failed to set user rebus: %v", err)
+	}
+
+	if err := puzzle.SetUserRebus(1, 1, "HEAT"); err != nil {
+		t.Fatalf("Failed to set user rebus: %v", err)
+	}
+
+	encoded, err := puz.EncodePuz(puzzle)
+	if err != nil {
+		t.Fatalf("Failed to encode puzzle: %v", err)
+	}
+
+	decoded, err := puz.DecodePuz(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode puzzle: %v", err)
+	}
+
+	if value, ok := decoded.UserRebus(0, 0); !ok || value != "HEARD" {
+		t.Errorf("Expected HEARD at (0, 0), found %q", value)
+	}
+
+	if value, ok := decoded.UserRebus(1, 1); !ok || value != "HEAT" {
+		t.Errorf("Expected HEAT at (1, 1), found %q", value)
+	}
+
+	for _, pos := range []puz.Position{{X: 0, Y: 0}, {X: 1, Y: 1}} {
+		if value, ok := decoded.Rebus(pos.X, pos.Y); !ok || value != "HEART" {
+			t.Errorf("Expected rebus HEART at %v, found %q", pos, value)
+		}
+	}
+}
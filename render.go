@@ -0,0 +1,17 @@
+package puz
+
+import (
+	"math"
+	"strconv"
+)
+
+// formatCoordinate formats a coordinate for SVG and PDF output with at most 2 decimal places and no trailing zeros.
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// cellFontSize returns the font size for an answer in a cell of size cell, given the width of the answer at a font size of 1.
+// Long rebus values are shrunk so they fit in the cell.
+func cellFontSize(cell float64, width float64) float64 {
+	return math.Min(cell*0.6, cell*0.9/width)
+}
@@ -0,0 +1,92 @@
+package puz
+
+import (
+	"slices"
+)
+
+// RenumberResult describes the changes made by Renumber
+type RenumberResult struct {
+	Orphaned Clues  // Clues that no longer match a word and were removed from the puzzle
+	Added    []Word // Words that had no clue, an empty placeholder clue was added for each
+}
+
+// Renumber recomputes the number and position of every clue from the current board.
+//
+// A clue keeps its text when its word still starts at the same position.
+// Otherwise it is matched to a word in the same direction that covers its old start, or that starts right after it when the old start became a solid square.
+// Clues that can not be matched are removed and returned as orphaned, words without a clue get a placeholder clue with empty text.
+// The clues are stored in the order they are written to a .puz file, so the puzzle can be encoded afterwards.
+func (p *Puzzle) Renumber() RenumberResult {
+	var result RenumberResult
+
+	words := p.Board.GetWords()
+	wordClues := make([]*Clue, len(words))
+	matched := make([]bool, len(p.clues))
+
+	// clues that still start a word
+	for i := range p.clues {
+		clue := &p.clues[i]
+
+		for j, word := range words {
+			if wordClues[j] == nil && word.Direction == clue.Direction && word.StartX == clue.StartX && word.StartY == clue.StartY {
+				wordClues[j] = clue
+				matched[i] = true
+				break
+			}
+		}
+	}
+
+	// clues whose start is now inside a word, or whose first square is now solid
+	for i := range p.clues {
+		if matched[i] {
+			continue
+		}
+
+		clue := &p.clues[i]
+		start := Position{clue.StartX, clue.StartY}
+
+		if p.Board.IsSolidSquare(start.X, start.Y) {
+			if clue.Direction == Across {
+				start.X++
+			} else {
+				start.Y++
+			}
+		}
+
+		for j, word := range words {
+			if wordClues[j] != nil || word.Direction != clue.Direction {
+				continue
+			}
+
+			if slices.Contains(p.wordCells(word), start) {
+				wordClues[j] = clue
+				matched[i] = true
+				break
+			}
+		}
+	}
+
+	for i, clue := range p.clues {
+		if !matched[i] {
+			result.Orphaned = append(result.Orphaned, clue)
+		}
+	}
+
+	clues := make(Clues, len(words))
+
+	for j, word := range words {
+		text := ""
+
+		if wordClues[j] != nil {
+			text = wordClues[j].Clue
+		} else {
+			result.Added = append(result.Added, word)
+		}
+
+		clues[j] = NewClue(text, word.Num, word.StartX, word.StartY, word.Direction)
+	}
+
+	p.SetClues(clues)
+
+	return result
+}
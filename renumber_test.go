@@ -0,0 +1,104 @@
+package puz_test
+
+import (
+	puz "github.com/cqb13/puz-parser"
+	"slices"
+	"testing"
+)
+
+func TestRenumberUnchanged(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "NYT-Nov2193.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode NYT-Nov2193.puz: %v", err)
+	}
+
+	original := slices.Clone(puzzle.Clues())
+
+	result := puzzle.Renumber()
+	if len(result.Orphaned) != 0 || len(result.Added) != 0 {
+		t.Fatalf("Expected no changes, found %+v", result)
+	}
+
+	if !slices.Equal(original, puzzle.Clues()) {
+		t.Fatalf("Clues changed after renumbering an unchanged grid")
+	}
+}
+
+func TestRenumberAfterEdit(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	acrossClue, _ := puzzle.GetClueByPos(0, 1, puz.Across)
+	acrossText := acrossClue.Clue
+
+	downClue, _ := puzzle.GetClueByPos(1, 0, puz.Down)
+	downText := downClue.Clue
+
+	// ACHED becomes .CHED, shortening 5 across and splitting 1 down into B and SH
+	puzzle.Board[1][0].Answer = puz.SolidSquare
+	puzzle.Board[1][0].Guess = puz.SolidSquare
+
+	result := puzzle.Renumber()
+
+	if len(result.Orphaned) != 1 || result.Orphaned[0].Clue != "Shell used for Unix commands" {
+		t.Fatalf("Expected 1 down to be orphaned, found %v", result.Orphaned)
+	}
+
+	if len(result.Added) != 1 || result.Added[0].StartX != 0 || result.Added[0].StartY != 2 || result.Added[0].Direction != puz.Down {
+		t.Fatalf("Expected a new down word at x: 0 y: 2, found %v", result.Added)
+	}
+
+	clue, ok := puzzle.GetClueByPos(1, 1, puz.Across)
+	if !ok || clue.Clue != acrossText {
+		t.Fatalf("Expected the shortened across clue to keep its text, found %v", clue)
+	}
+
+	clue, ok = puzzle.GetClueByPos(1, 0, puz.Down)
+	if !ok || clue.Clue != downText {
+		t.Fatalf("Expected the down clue to keep its text, found %v", clue)
+	}
+
+	for _, issue := range puzzle.Validate() {
+		if issue.Severity == puz.SeverityError && issue.Kind != puz.MissingClueIssue {
+			t.Errorf("Found unexpected issue after renumbering: %v", issue)
+		}
+	}
+
+	encoded, err := puz.EncodePuz(puzzle)
+	if err != nil {
+		t.Fatalf("Failed to encode renumbered puzzle: %v", err)
+	}
+
+	decoded, err := puz.DecodePuz(encoded)
+	if err != nil {
+		t.Fatalf("Failed to decode renumbered puzzle: %v", err)
+	}
+
+	if !slices.Equal(decoded.Clues(), puzzle.Clues()) {
+		t.Fatalf("Clues changed after encoding the renumbered puzzle")
+	}
+}
+
+func TestRenumberOrphans(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	// REED is removed entirely
+	for x := 1; x < 5; x++ {
+		puzzle.Board[4][x].Answer = puz.SolidSquare
+	}
+
+	result := puzzle.Renumber()
+
+	if len(result.Orphaned) != 1 || result.Orphaned[0].StartY != 4 {
+		t.Fatalf("Expected the bottom across clue to be orphaned, found %v", result.Orphaned)
+	}
+
+	if puzzle.ExpectedClues() != len(puzzle.Clues()) || len(puzzle.Clues()) != len(puzzle.Board.GetWords()) {
+		t.Fatalf("Expected one clue per word")
+	}
+}
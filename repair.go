@@ -0,0 +1,57 @@
+package puz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+)
+
+// RepairReport lists the checksums that were rewritten by RepairPuz
+type RepairReport struct {
+	Repairs []ChecksumMismatch // Expected is the old value, Calculated is the value written
+}
+
+// Repaired returns true if any checksum was rewritten
+func (r RepairReport) Repaired() bool {
+	return len(r.Repairs) > 0
+}
+
+func (r RepairReport) String() string {
+	if !r.Repaired() {
+		return "No checksums needed repair"
+	}
+
+	var out strings.Builder
+
+	for i, repair := range r.Repairs {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+
+		out.WriteString(repair.String())
+	}
+
+	return out.String()
+}
+
+// RepairPuz rewrites every incorrect header and extra section checksum in data.
+// All other bytes are left unchanged, the original data is not modified.
+func RepairPuz(data []byte) ([]byte, RepairReport, error) {
+	_, mismatches, err := DecodePuzWithOptions(data, DecodeOptions{Lenient: true})
+	if err != nil {
+		return nil, RepairReport{}, err
+	}
+
+	repaired := bytes.Clone(data)
+
+	for _, mismatch := range mismatches {
+		switch mismatch.Checksum {
+		case MaskedLowChecksum, MaskedHighChecksum:
+			binary.LittleEndian.PutUint32(repaired[mismatch.offset:], uint32(mismatch.Calculated))
+		default:
+			binary.LittleEndian.PutUint16(repaired[mismatch.offset:], uint16(mismatch.Calculated))
+		}
+	}
+
+	return repaired, RepairReport{mismatches}, nil
+}
@@ -0,0 +1,75 @@
+package puz_test
+
+import (
+	"bytes"
+	puz "github.com/cqb13/puz-parser"
+	"testing"
+)
+
+func TestRepairPuz(t *testing.T) {
+	testCases := []string{
+		"Crossword-EXT-Rebus.puz",
+		"NYT-Nov2193.puz",
+	}
+
+	for _, name := range testCases {
+		t.Run(name, func(t *testing.T) {
+			original := loadFile(t, name)
+			data := corruptChecksums(t, name)
+
+			// corrupt the global checksum as well
+			data[0] ^= 0xFF
+
+			repaired, report, err := puz.RepairPuz(data)
+			if err != nil {
+				t.Fatalf("Failed to repair %s: %v", name, err)
+			}
+
+			if len(report.Repairs) != 3 {
+				t.Fatalf("Expected 3 repairs, found:\n%s", report)
+			}
+
+			if !bytes.Equal(original, repaired) {
+				t.Fatalf("Repaired bytes do not match original for %s\n\noriginal:\n%s\n\nnew:\n%s", name, buildHex(original), buildHex(repaired))
+			}
+
+			if _, err := puz.DecodePuz(repaired); err != nil {
+				t.Fatalf("Failed to decode repaired %s: %v", name, err)
+			}
+		})
+	}
+}
+
+func TestRepairPuzPreamble(t *testing.T) {
+	original := loadFile(t, "Crossword-PreAndPost.puz")
+	data := bytes.Clone(original)
+
+	index := bytes.Index(data, []byte("ACROSS&DOWN"))
+	data[index-2] ^= 0xFF
+
+	repaired, report, err := puz.RepairPuz(data)
+	if err != nil {
+		t.Fatalf("Failed to repair Crossword-PreAndPost.puz: %v", err)
+	}
+
+	if len(report.Repairs) != 1 || report.Repairs[0].Checksum != puz.GlobalChecksum {
+		t.Fatalf("Expected only the global checksum to be repaired, found:\n%s", report)
+	}
+
+	if !bytes.Equal(original, repaired) {
+		t.Fatalf("Repaired bytes do not match original")
+	}
+}
+
+func TestRepairPuzValidFile(t *testing.T) {
+	data := loadFile(t, "Crossword.puz")
+
+	repaired, report, err := puz.RepairPuz(data)
+	if err != nil {
+		t.Fatalf("Failed to repair Crossword.puz: %v", err)
+	}
+
+	if report.Repaired() || !bytes.Equal(data, repaired) {
+		t.Fatalf("Expected a valid file to be left unchanged, found:\n%s", report)
+	}
+}
@@ -1,8 +1,13 @@
 package puz
 
 import (
+	"context"
 	"fmt"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // isLetter reports if char is either a-z or A-Z.
@@ -14,32 +19,94 @@ func isLetter(char byte) bool {
 	return false
 }
 
+// isScrambledLetter reports if char is a letter changed by scrambling, only A-Z are scrambled.
+func isScrambledLetter(char byte) bool {
+	return char >= 'A' && char <= 'Z'
+}
+
+// isSkippedInScramble reports if char is left in place when scrambling.
+//
+// Solid squares, digits, and printable ASCII symbols are skipped, matching Across Lite.
+func isSkippedInScramble(char byte) bool {
+	if char == SolidSquare || char == DiagramlessSolidSquare {
+		return true
+	}
+
+	return char > ' ' && char < 0x7F && !isLetter(char)
+}
+
 // createScramble converts the crossword board into a string used in the scrambling/unscrambling algorithm.
 //
-// The string is formed by starting in the top left corner of the board and traversing down each column adding each letter and skipping any solid squares.
-// returns NonLetterCharactersInScrambleError if the board contains a non letter character.
+// The string is formed by starting in the top left corner of the board and traversing down each column adding each uppercase letter and skipping solid squares, digits, and symbols.
+// returns an UnscramblableCellsError listing every lowercase, blank, or non ASCII answer.
 func createScramble(puzzle *Puzzle) (string, error) {
 	height := puzzle.Board.Height()
 	width := puzzle.Board.Width()
 	var scramble strings.Builder
+	var invalid []Position
 
 	for x := range width {
 		for y := range height {
 			ch := puzzle.Board[y][x].Answer
-			if isLetter(ch) {
-				scramble.WriteString(string(ch))
+			if isScrambledLetter(ch) {
+				scramble.WriteByte(ch)
 				continue
 			}
 
-			if ch != SolidSquare && ch != DiagramlessSolidSquare {
-				return "", NonLetterCharactersInScrambleError
+			if !isSkippedInScramble(ch) {
+				invalid = append(invalid, Position{x, y})
 			}
 		}
 	}
 
+	if len(invalid) > 0 {
+		// report cells in reading order
+		slices.SortFunc(invalid, func(a Position, b Position) int {
+			if a.Y != b.Y {
+				return a.Y - b.Y
+			}
+
+			return a.X - b.X
+		})
+
+		return "", &UnscramblableCellsError{
+			invalid,
+		}
+	}
+
 	return scramble.String(), nil
 }
 
+// createGuessScramble builds the string the scrambled checksum is checked against from the players guesses.
+//
+// Guesses are read in the same order as createScramble and uppercased, the bool is false if a letter cell has no letter guess.
+func createGuessScramble(puzzle *Puzzle) (string, bool) {
+	height := puzzle.Board.Height()
+	width := puzzle.Board.Width()
+	var guesses strings.Builder
+
+	for x := range width {
+		for y := range height {
+			if !isScrambledLetter(puzzle.Board[y][x].Answer) {
+				continue
+			}
+
+			guess := puzzle.Board[y][x].Guess
+			if !isLetter(guess) {
+				return "", false
+			}
+
+			if guess >= 'a' && guess <= 'z' {
+				guess -= 'a' - 'A'
+			}
+
+			guesses.WriteByte(guess)
+		}
+	}
+
+	return guesses.String(), true
+}
+
 // updatePuzzleSolution takes in the string used in scrambling process and puts the letters back in the board in the appropriate places.
 func updatePuzzleSolution(puzzle *Puzzle, newSol string) {
 	height := puzzle.Board.Height()
@@ -48,7 +115,7 @@ func updatePuzzleSolution(puzzle *Puzzle, newSol string) {
 
 	for x := range width {
 		for y := range height {
-			if isLetter(puzzle.Board[y][x].Answer) {
+			if isScrambledLetter(puzzle.Board[y][x].Answer) {
 				puzzle.Board[y][x].Answer = newSol[n]
 				n++
 			}
@@ -79,7 +146,7 @@ func keyToBytes(key int) ([]byte, error) {
 
 // scramble scrambles a crosswords answer board.
 //
-// returns TooFewCharactersToScrambleError if the board has less than 12 characters. Also fails if the key is invalid or non letter characters are found in the board.
+// returns TooFewCharactersToScrambleError if the board has less than 12 characters. Also fails if the key is invalid or the board has answers that can not be scrambled.
 func scramble(puzzle *Puzzle, key int) error {
 	keyDigits, err := keyToBytes(key)
 	if err != nil {
@@ -95,6 +162,9 @@ func scramble(puzzle *Puzzle, key int) error {
 		return TooFewCharactersToScrambleError
 	}
 
+	// the checksum is for the unscrambled solution so a key can be verified when unscrambling
+	solutionChecksum := checksumRegion([]byte(scramble), 0)
+
 	for _, digit := range keyDigits {
 		lastScramble := scramble
 		scramble = ""
@@ -116,7 +186,7 @@ func scramble(puzzle *Puzzle, key int) error {
 
 	updatePuzzleSolution(puzzle, scramble)
 	puzzle.scramble.scrambledTag = 4
-	puzzle.scramble.scrambledChecksum = checksumRegion([]byte(scramble), 0)
+	puzzle.scramble.scrambledChecksum = solutionChecksum
 
 	return nil
 }
@@ -145,7 +215,7 @@ func scrambleString(unscrambled string) string {
 
 // unscramble unscrambles a crosswords board.
 //
-// returns TooFewCharactersToUncrambleError if the board has less than 12 characters. Also fails if the key is invalid or non letter characters are found in the board.
+// returns TooFewCharactersToUncrambleError if the board has less than 12 characters. Also fails if the key is invalid or the board has answers that can not be scrambled.
 func unscramble(puzzle *Puzzle, key int) error {
 	keyDigits, err := keyToBytes(key)
 	if err != nil {
@@ -161,6 +231,21 @@ func unscramble(puzzle *Puzzle, key int) error {
 		return TooFewCharactersToUnscrambleError
 	}
 
+	solution = unscrambleSolution(solution, keyDigits)
+
+	if checksumRegion([]byte(solution), 0) != puzzle.scramble.scrambledChecksum {
+		return IncorrectKeyProvidedError
+	}
+
+	updatePuzzleSolution(puzzle, solution)
+	puzzle.scramble.scrambledTag = 0
+	puzzle.scramble.scrambledChecksum = 0x0000
+
+	return nil
+}
+
+// unscrambleSolution reverses the scrambling algorithm on a scrambled solution string using the key digits.
+func unscrambleSolution(solution string, keyDigits []byte) string {
 	for round := 3; round >= 0; round-- {
 		digit := int(keyDigits[round])
 
@@ -178,15 +263,7 @@ func unscramble(puzzle *Puzzle, key int) error {
 		solution = undo.String()
 	}
 
-	if checksumRegion([]byte(solution), 0) != puzzle.scramble.scrambledChecksum {
-		return IncorrectKeyProvidedError
-	}
-
-	updatePuzzleSolution(puzzle, solution)
-	puzzle.scramble.scrambledTag = 0
-	puzzle.scramble.scrambledChecksum = 0x0000
-
-	return nil
+	return solution
 }
 
 func unscrambleString(scrambled string) string {
@@ -214,3 +291,80 @@ func unshiftString(s string, num int) string {
 	num = num % len(s)
 	return s[len(s)-num:] + s[:len(s)-num]
 }
+
+// recoverScrambleKey tries every valid key against the scrambled checksum and returns the lowest key that matches.
+//
+// Keys are checked in ascending order by a worker per CPU, workers stop once a match is found or ctx is cancelled.
+// returns IncorrectKeyProvidedError if no key matches.
+func recoverScrambleKey(ctx context.Context, puzzle *Puzzle) (int, error) {
+	scrambled, err := createScramble(puzzle)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(scrambled) < 12 {
+		return 0, TooFewCharactersToUnscrambleError
+	}
+
+	keys := make(chan int)
+	var found atomic.Int64
+	var wg sync.WaitGroup
+
+	found.Store(-1)
+
+	for range runtime.GOMAXPROCS(0) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for key := range keys {
+				keyDigits, err := keyToBytes(key)
+				if err != nil {
+					continue
+				}
+
+				if checksumRegion([]byte(unscrambleSolution(scrambled, keyDigits)), 0) != puzzle.scramble.scrambledChecksum {
+					continue
+				}
+
+				// keep the lowest match, keys handed out before the first match may still match
+				for {
+					current := found.Load()
+					if current != -1 && current <= int64(key) {
+						break
+					}
+
+					if found.CompareAndSwap(current, int64(key)) {
+						break
+					}
+				}
+			}
+		}()
+	}
+
+	for key := 1111; key <= 9999 && found.Load() == -1; key++ {
+		select {
+		case keys <- key:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	close(keys)
+	wg.Wait()
+
+	// a key found before the context was cancelled is still returned
+	key := found.Load()
+	if key != -1 {
+		return int(key), nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, IncorrectKeyProvidedError
+}
@@ -1,7 +1,10 @@
 package puz_test
 
 import (
+	"context"
+	"errors"
 	puz "github.com/cqb13/puz-parser"
+	"slices"
 	"testing"
 )
 
@@ -104,3 +107,201 @@ func TestScramble(t *testing.T) {
 		})
 	}
 }
+
+func TestScrambleRoundTrip(t *testing.T) {
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			puzzle, err := puz.DecodePuz(loadFile(t, tc.plainFile))
+			if err != nil {
+				t.Fatalf("Failed to decode %s: %v", tc.plainFile, err)
+			}
+
+			original, _ := puz.DecodePuz(loadFile(t, tc.plainFile))
+
+			if err := puzzle.Scramble(4321); err != nil {
+				t.Fatalf("Puzzle %s failed to scramble: %v", tc.plainFile, err)
+			}
+
+			if err := puzzle.Unscramble(4321); err != nil {
+				t.Fatalf("Puzzle %s failed to unscramble after scrambling: %v", tc.plainFile, err)
+			}
+
+			for y := range puzzle.Board {
+				for x := range puzzle.Board[y] {
+					if puzzle.Board[y][x].Answer != original.Board[y][x].Answer {
+						t.Fatalf("Cell x: %d y: %d mismatch after round trip (%c != %c)", x, y, puzzle.Board[y][x].Answer, original.Board[y][x].Answer)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRecoverScrambleKey(t *testing.T) {
+	keyTests := []struct {
+		name string
+		key  int
+	}{
+		{"Crossword-Scrambled.puz", 1234},
+		{"NYT-Locked.puz", 7844},
+		{"NYT-Diagramless.puz", 3285},
+	}
+
+	for _, tc := range keyTests {
+		t.Run(tc.name, func(t *testing.T) {
+			puzzle, err := puz.DecodePuz(loadFile(t, tc.name))
+			if err != nil {
+				t.Fatalf("Failed to decode %s: %v", tc.name, err)
+			}
+
+			key, err := puzzle.RecoverScrambleKey(context.Background())
+			if err != nil {
+				t.Fatalf("Failed to recover key for %s: %v", tc.name, err)
+			}
+
+			if key != tc.key {
+				t.Fatalf("Expected key %d, found %d", tc.key, key)
+			}
+
+			if err := puzzle.Unscramble(key); err != nil {
+				t.Fatalf("Failed to unscramble %s with the recovered key: %v", tc.name, err)
+			}
+		})
+	}
+}
+
+func TestRecoverScrambleKeyErrors(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	if _, err := puzzle.RecoverScrambleKey(context.Background()); err != puz.PuzzleIsUnscrambledError {
+		t.Errorf("Expected PuzzleIsUnscrambledError, found %v", err)
+	}
+
+	puzzle, err = puz.DecodePuz(loadFile(t, "NYT-Locked.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode NYT-Locked.puz: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := puzzle.RecoverScrambleKey(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, found %v", err)
+	}
+
+	// changing a scrambled answer means no key matches the checksum
+	cell := &puzzle.Board[0][0]
+	if cell.Answer == 'Q' {
+		cell.Answer = 'Z'
+	} else {
+		cell.Answer = 'Q'
+	}
+
+	if _, err := puzzle.RecoverScrambleKey(context.Background()); !errors.Is(err, puz.IncorrectKeyProvidedError) {
+		t.Errorf("Expected IncorrectKeyProvidedError, found %v", err)
+	}
+}
+
+func TestScrambleSkippedCells(t *testing.T) {
+	testFiles := []string{
+		"Crossword.puz",
+		"NYT-Nov2193.puz",
+		"washpost.puz",
+	}
+
+	for _, name := range testFiles {
+		t.Run(name, func(t *testing.T) {
+			puzzle, err := puz.DecodePuz(loadFile(t, name))
+			if err != nil {
+				t.Fatalf("Failed to decode %s: %v", name, err)
+			}
+
+			// digits and symbols are left in place
+			puzzle.Board[0][0].Answer = '7'
+			puzzle.Board[0][1].Answer = '&'
+
+			original := make([][]byte, len(puzzle.Board))
+			for y := range puzzle.Board {
+				for x := range puzzle.Board[y] {
+					original[y] = append(original[y], puzzle.Board[y][x].Answer)
+				}
+			}
+
+			if err := puzzle.Scramble(1357); err != nil {
+				t.Fatalf("Failed to scramble %s: %v", name, err)
+			}
+
+			if puzzle.Board[0][0].Answer != '7' || puzzle.Board[0][1].Answer != '&' {
+				t.Fatalf("Skipped cells changed during scramble")
+			}
+
+			encoded, err := puz.EncodePuz(puzzle)
+			if err != nil {
+				t.Fatalf("Failed to encode scrambled %s: %v", name, err)
+			}
+
+			decoded, err := puz.DecodePuz(encoded)
+			if err != nil {
+				t.Fatalf("Failed to decode scrambled %s: %v", name, err)
+			}
+
+			if err := decoded.Unscramble(1357); err != nil {
+				t.Fatalf("Failed to unscramble %s: %v", name, err)
+			}
+
+			for y := range decoded.Board {
+				for x := range decoded.Board[y] {
+					if decoded.Board[y][x].Answer != original[y][x] {
+						t.Fatalf("Cell x: %d y: %d mismatch after round trip (%c != %c)", x, y, decoded.Board[y][x].Answer, original[y][x])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestScrambleUnscramblableCells(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "All-Sections-Sorted.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode All-Sections-Sorted.puz: %v", err)
+	}
+
+	var blanks []puz.Position
+	for y := range puzzle.Board {
+		for x := range puzzle.Board[y] {
+			if puzzle.Board[y][x].Answer == puz.EmptySolutionSquare {
+				blanks = append(blanks, puz.Position{X: x, Y: y})
+			}
+		}
+	}
+
+	err = puzzle.Scramble(1234)
+
+	var cellsErr *puz.UnscramblableCellsError
+	if !errors.As(err, &cellsErr) {
+		t.Fatalf("Expected UnscramblableCellsError, found %v", err)
+	}
+
+	if !errors.Is(err, puz.NonLetterCharactersInScrambleError) {
+		t.Errorf("Expected the error to wrap NonLetterCharactersInScrambleError")
+	}
+
+	if !slices.Equal(cellsErr.Cells(), blanks) {
+		t.Fatalf("Expected cells %v, found %v", blanks, cellsErr.Cells())
+	}
+
+	puzzle, err = puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	puzzle.Board[1][2].Answer = 'q'
+
+	err = puzzle.Scramble(1234)
+	if !errors.As(err, &cellsErr) || !slices.Equal(cellsErr.Cells(), []puz.Position{{X: 2, Y: 1}}) {
+		t.Fatalf("Expected the lowercase cell to be reported, found %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package puz
+
+// Slots indexes the words of a board so the words at a cell and the crossings of a word can be found without scanning.
+//
+// Slots is a snapshot, it must be rebuilt after the board is changed.
+type Slots struct {
+	words  []Word
+	cells  [][]Position
+	across map[Position]int // index in words of the across word covering a cell
+	down   map[Position]int // index in words of the down word covering a cell
+	clues  map[slotKey]*Clue
+}
+
+// Crossing is a letter shared by a word and a word in the other direction
+type Crossing struct {
+	Position         // The shared cell
+	Index      int   // The index of the shared letter in the word
+	Word       *Word // The crossing word
+	CrossIndex int   // The index of the shared letter in the crossing word
+}
+
+type slotKey struct {
+	x   int
+	y   int
+	dir Direction
+}
+
+// NewSlots builds a Slots index from the words of board.
+func NewSlots(board Board) *Slots {
+	slots := Slots{
+		board.GetWords(),
+		nil,
+		make(map[Position]int),
+		make(map[Position]int),
+		make(map[slotKey]*Clue),
+	}
+
+	slots.cells = make([][]Position, len(slots.words))
+
+	for i, word := range slots.words {
+		x := word.StartX
+		y := word.StartY
+
+		for board.inBounds(x, y) && !board.IsSolidSquare(x, y) {
+			pos := Position{x, y}
+			slots.cells[i] = append(slots.cells[i], pos)
+
+			if word.Direction == Across {
+				slots.across[pos] = i
+				x++
+			} else {
+				slots.down[pos] = i
+				y++
+			}
+		}
+	}
+
+	return &slots
+}
+
+// Slots builds a Slots index for the board with each word linked to its clue.
+// The linked clues point into the puzzle and should not be used after the clues are replaced.
+func (p *Puzzle) Slots() *Slots {
+	slots := NewSlots(p.Board)
+
+	for i := range p.clues {
+		clue := &p.clues[i]
+		slots.clues[slotKey{clue.StartX, clue.StartY, clue.Direction}] = clue
+	}
+
+	return slots
+}
+
+// Words returns every word in the index in clue order.
+func (s *Slots) Words() []Word {
+	return s.words
+}
+
+// WordsAt returns the across and down words that contain the cell at (x, y), either is nil if there is no word.
+func (s *Slots) WordsAt(x int, y int) (*Word, *Word) {
+	var across, down *Word
+
+	if i, ok := s.across[Position{x, y}]; ok {
+		across = &s.words[i]
+	}
+
+	if i, ok := s.down[Position{x, y}]; ok {
+		down = &s.words[i]
+	}
+
+	return across, down
+}
+
+// Cells returns the positions of the letters in word, nil if the word is not in the index.
+func (s *Slots) Cells(word Word) []Position {
+	i, ok := s.index(word)
+	if !ok {
+		return nil
+	}
+
+	return s.cells[i]
+}
+
+// Crossings returns a Crossing for every letter of word that is also part of a word in the other direction.
+func (s *Slots) Crossings(word Word) []Crossing {
+	i, ok := s.index(word)
+	if !ok {
+		return nil
+	}
+
+	crossIndex := s.down
+	if word.Direction == Down {
+		crossIndex = s.across
+	}
+
+	var crossings []Crossing
+
+	for index, pos := range s.cells[i] {
+		j, ok := crossIndex[pos]
+		if !ok {
+			continue
+		}
+
+		crossing := &s.words[j]
+
+		crossings = append(crossings, Crossing{
+			pos,
+			index,
+			crossing,
+			(pos.X - crossing.StartX) + (pos.Y - crossing.StartY),
+		})
+	}
+
+	return crossings
+}
+
+// ClueFor returns the clue for word, only indexes built with Puzzle.Slots have clues.
+func (s *Slots) ClueFor(word Word) (*Clue, bool) {
+	clue, ok := s.clues[slotKey{word.StartX, word.StartY, word.Direction}]
+	return clue, ok
+}
+
+// index returns the index of the word that starts at the same position in the same direction.
+func (s *Slots) index(word Word) (int, bool) {
+	start := Position{word.StartX, word.StartY}
+
+	lookup := s.across
+	if word.Direction == Down {
+		lookup = s.down
+	}
+
+	i, ok := lookup[start]
+	if !ok || s.words[i].StartX != word.StartX || s.words[i].StartY != word.StartY {
+		return 0, false
+	}
+
+	return i, true
+}
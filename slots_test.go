@@ -0,0 +1,114 @@
+package puz_test
+
+import (
+	puz "github.com/cqb13/puz-parser"
+	"slices"
+	"testing"
+)
+
+func TestSlotsWordsAt(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	slots := puzzle.Slots()
+
+	across, down := slots.WordsAt(2, 1)
+	if across == nil || down == nil {
+		t.Fatalf("Expected an across and a down word at x: 2 y: 1")
+	}
+
+	if across.Word != "ACHED" || across.Num != 5 || down.Word != "SHORE" || down.Num != 3 {
+		t.Fatalf("Found unexpected words %v and %v", across, down)
+	}
+
+	if across, down := slots.WordsAt(4, 0); across != nil || down != nil {
+		t.Fatalf("Expected no words at a solid square")
+	}
+
+	cells := slots.Cells(*down)
+	expected := []puz.Position{{X: 2, Y: 0}, {X: 2, Y: 1}, {X: 2, Y: 2}, {X: 2, Y: 3}, {X: 2, Y: 4}}
+	if !slices.Equal(cells, expected) {
+		t.Fatalf("Expected %v, found %v", expected, cells)
+	}
+}
+
+func TestSlotsCrossings(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	slots := puzzle.Slots()
+	across, _ := slots.WordsAt(0, 2)
+
+	crossings := slots.Crossings(*across)
+	if len(crossings) != len(across.Word) {
+		t.Fatalf("Expected every letter of %s to be crossed, found %d", across.Word, len(crossings))
+	}
+
+	for _, crossing := range crossings {
+		if crossing.Word.Direction != puz.Down {
+			t.Fatalf("Expected crossing words to be down words")
+		}
+
+		if across.Word[crossing.Index] != crossing.Word.Word[crossing.CrossIndex] {
+			t.Fatalf("Letter %d of %s does not match letter %d of %s", crossing.Index, across.Word, crossing.CrossIndex, crossing.Word.Word)
+		}
+	}
+
+	// letter 3 of STONE is crossed by 3 down at its third letter
+	if crossings[2].Word.Num != 3 || crossings[2].CrossIndex != 2 {
+		t.Fatalf("Found unexpected crossing %+v", crossings[2])
+	}
+}
+
+func TestSlotsClueFor(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	slots := puzzle.Slots()
+
+	for _, word := range slots.Words() {
+		clue, ok := slots.ClueFor(word)
+		if !ok || clue.Num != word.Num || clue.Direction != word.Direction {
+			t.Fatalf("Failed to find the clue for %d %v", word.Num, word.Direction)
+		}
+	}
+
+	word := slots.Words()[0]
+	clue, _ := slots.ClueFor(word)
+	clue.Clue = "Changed"
+
+	if found, _ := puzzle.GetClueByPos(word.StartX, word.StartY, word.Direction); found.Clue != "Changed" {
+		t.Fatalf("Expected the clue to point into the puzzle")
+	}
+
+	if _, ok := puz.NewSlots(puzzle.Board).ClueFor(word); ok {
+		t.Fatalf("Expected an index built from a board to have no clues")
+	}
+}
+
+func TestGetCluePointers(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	clue, _ := puzzle.GetClueByNum(1, puz.Across)
+	clue.Clue = "By number"
+
+	clue, _ = puzzle.GetClueByPos(0, 0, puz.Down)
+	clue.Clue = "By position"
+
+	if clue, _ := puzzle.GetClueByPos(0, 0, puz.Across); clue.Clue != "By number" {
+		t.Errorf("Expected GetClueByNum to return a pointer into the puzzle")
+	}
+
+	if clue, _ := puzzle.GetClueByNum(1, puz.Down); clue.Clue != "By position" {
+		t.Errorf("Expected GetClueByPos to return a pointer into the puzzle")
+	}
+}
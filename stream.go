@@ -0,0 +1,74 @@
+package puz
+
+import (
+	"io"
+)
+
+// DefaultMaxDecodeSize is the most data a Decoder will read unless changed with SetMaxSize
+const DefaultMaxDecodeSize int64 = 4 << 20
+
+// A Decoder reads a puz file from an input stream.
+type Decoder struct {
+	reader  io.Reader
+	maxSize int64
+}
+
+// NewDecoder returns a Decoder that reads from r, limited to DefaultMaxDecodeSize bytes.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r,
+		DefaultMaxDecodeSize,
+	}
+}
+
+// SetMaxSize changes the most data the Decoder will read before failing with DataTooLargeError.
+// A size of 0 or less removes the limit.
+func (d *Decoder) SetMaxSize(size int64) {
+	d.maxSize = size
+}
+
+// Decode reads the input stream until EOF and parses it the same way as DecodePuz.
+//
+// Data before the puz header and after the last extra section is kept as the Preamble and Postscript, so the whole stream is consumed.
+// Returns DataTooLargeError if the stream is longer than the max size.
+func (d *Decoder) Decode() (*Puzzle, error) {
+	reader := d.reader
+	if d.maxSize > 0 {
+		reader = io.LimitReader(d.reader, d.maxSize+1)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if d.maxSize > 0 && int64(len(data)) > d.maxSize {
+		return nil, DataTooLargeError
+	}
+
+	return DecodePuz(data)
+}
+
+// An Encoder writes a puz file to an output stream.
+type Encoder struct {
+	writer io.Writer
+}
+
+// NewEncoder returns an Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w,
+	}
+}
+
+// Encode writes the puzzle to the output stream in the same form as EncodePuz.
+// Nothing is written if the puzzle fails to encode.
+func (e *Encoder) Encode(puzzle *Puzzle) error {
+	writer, err := encodePuz(puzzle)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.writeTo(e.writer)
+	return err
+}
@@ -0,0 +1,82 @@
+package puz_test
+
+import (
+	"bytes"
+	puz "github.com/cqb13/puz-parser"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	testCases := []string{
+		"Crossword.puz",
+		"Crossword-PreAndPost.puz",
+		"All-Sections-Unsorted.puz",
+		"NYT-Nov2193.puz",
+	}
+
+	for _, name := range testCases {
+		t.Run(name, func(t *testing.T) {
+			data := loadFile(t, name)
+
+			puzzle, err := puz.NewDecoder(bytes.NewReader(data)).Decode()
+			if err != nil {
+				t.Fatalf("Failed to decode %s from a reader: %v", name, err)
+			}
+
+			var out bytes.Buffer
+			err = puz.NewEncoder(&out).Encode(puzzle)
+			if err != nil {
+				t.Fatalf("Failed to encode %s to a writer: %v", name, err)
+			}
+
+			if !bytes.Equal(data, out.Bytes()) {
+				t.Errorf("Encoded bytes do not match original for %s", name)
+			}
+		})
+	}
+}
+
+func TestDecoderMaxSize(t *testing.T) {
+	data := loadFile(t, "Crossword.puz")
+
+	decoder := puz.NewDecoder(bytes.NewReader(data))
+	decoder.SetMaxSize(int64(len(data) - 1))
+
+	if _, err := decoder.Decode(); err != puz.DataTooLargeError {
+		t.Fatalf("Expected DataTooLargeError, found %v", err)
+	}
+
+	decoder = puz.NewDecoder(bytes.NewReader(data))
+	decoder.SetMaxSize(int64(len(data)))
+
+	if _, err := decoder.Decode(); err != nil {
+		t.Fatalf("Failed to decode data at exactly the max size: %v", err)
+	}
+}
+
+func TestDecoderErrorsMatch(t *testing.T) {
+	data := loadFile(t, "Crossword.puz")
+	data = data[:len(data)/2]
+
+	_, expected := puz.DecodePuz(data)
+	_, err := puz.NewDecoder(bytes.NewReader(data)).Decode()
+
+	if expected == nil || err == nil || err.Error() != expected.Error() {
+		t.Fatalf("Expected the same error as DecodePuz, expected %v, found %v", expected, err)
+	}
+}
+
+func TestEncoderError(t *testing.T) {
+	puzzle := puz.NewPuzzle(3, 3)
+	puzzle.Extras.RebusTable = nil
+	puzzle.AddExtraSection(puz.RebusTableSection)
+
+	var out bytes.Buffer
+	if err := puz.NewEncoder(&out).Encode(puzzle); err == nil {
+		t.Fatalf("Expected an error encoding a rebus table section without a table")
+	}
+
+	if out.Len() != 0 {
+		t.Fatalf("Wrote %d bytes for a puzzle that failed to encode", out.Len())
+	}
+}
@@ -0,0 +1,144 @@
+package puz
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// RenderMode selects which letters RenderSVG draws in the grid
+type RenderMode int
+
+const (
+	RenderBlank    RenderMode = iota // An empty grid for solving
+	RenderSolution                   // The answer key
+	RenderGuesses                    // The players current guesses with checked and revealed squares shaded
+)
+
+var renderModeStrMap = map[RenderMode]string{
+	RenderBlank:    "Blank",
+	RenderSolution: "Solution",
+	RenderGuesses:  "Guesses",
+}
+
+func (m RenderMode) String() string {
+	return renderModeStrMap[m]
+}
+
+// DefaultSVGCellSize is the width and height of a cell when SVGOptions.CellSize is not set
+const DefaultSVGCellSize = 32
+
+const (
+	svgBorderWidth     = 2
+	svgFontFamily      = "Helvetica, Arial, sans-serif"
+	svgSolidColor      = "#000000"
+	svgGivenColor      = "#d9d9d9" // ContentGiven squares
+	svgIncorrectColor  = "#f4c7c3" // CurrentlyIncorrect squares
+	svgPreviousColor   = "#fbe5e3" // PreviouslyIncorrect squares
+	svgLetterCharWidth = 0.6       // Approximate width of a capital letter in ems
+)
+
+// SVGOptions controls the output of RenderSVG
+type SVGOptions struct {
+	Mode     RenderMode // The letters to draw, defaults to RenderBlank
+	CellSize int        // The width and height of a cell in pixels, defaults to DefaultSVGCellSize
+}
+
+// RenderSVG draws the grid of p as an SVG image.
+//
+// Solid squares are filled, clue numbers come from Board.GetWords, and circled squares get a circle.
+// Rebus answers and guesses are drawn in full and shrunk to fit the cell.
+// In RenderGuesses mode squares marked ContentGiven, CurrentlyIncorrect, or PreviouslyIncorrect are shaded.
+// The output only depends on the puzzle and options so it can be compared byte for byte.
+// Returns PuzzleIsScrambledError for RenderSolution when the solution is scrambled.
+func RenderSVG(p *Puzzle, opts SVGOptions) ([]byte, error) {
+	if opts.Mode == RenderSolution && p.Scrambled() {
+		return nil, PuzzleIsScrambledError
+	}
+
+	size := opts.CellSize
+	if size <= 0 {
+		size = DefaultSVGCellSize
+	}
+
+	border := float64(svgBorderWidth)
+	width := float64(p.Board.Width()*size) + 2*border
+	height := float64(p.Board.Height()*size) + 2*border
+
+	var svg strings.Builder
+
+	fmt.Fprintf(&svg, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s\" height=\"%s\" viewBox=\"0 0 %s %s\">\n", formatCoordinate(width), formatCoordinate(height), formatCoordinate(width), formatCoordinate(height))
+	fmt.Fprintf(&svg, "<rect x=\"0\" y=\"0\" width=\"%s\" height=\"%s\" fill=\"#ffffff\"/>\n", formatCoordinate(width), formatCoordinate(height))
+
+	numbers := p.Board.cellNumbers()
+
+	for y := range p.Board.Height() {
+		for x := range p.Board.Width() {
+			left := border + float64(x*size)
+			top := border + float64(y*size)
+
+			renderSVGCell(&svg, p, opts.Mode, x, y, left, top, float64(size), numbers[y][x])
+		}
+	}
+
+	fmt.Fprintf(&svg, "<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" fill=\"none\" stroke=\"#000000\" stroke-width=\"%d\"/>\n", formatCoordinate(border/2), formatCoordinate(border/2), formatCoordinate(width-border), formatCoordinate(height-border), svgBorderWidth)
+	svg.WriteString("</svg>\n")
+
+	return []byte(svg.String()), nil
+}
+
+func renderSVGCell(svg *strings.Builder, p *Puzzle, mode RenderMode, x int, y int, left float64, top float64, size float64, number int) {
+	cell := p.Board[y][x]
+
+	fill := "#ffffff"
+	switch {
+	case p.Board.IsSolidSquare(x, y):
+		fill = svgSolidColor
+	case mode != RenderGuesses:
+	case cell.HasMarkup(CurrentlyIncorrect):
+		fill = svgIncorrectColor
+	case cell.HasMarkup(ContentGiven):
+		fill = svgGivenColor
+	case cell.HasMarkup(PreviouslyIncorrect):
+		fill = svgPreviousColor
+	}
+
+	fmt.Fprintf(svg, "<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" fill=\"%s\" stroke=\"#000000\" stroke-width=\"1\"/>\n", formatCoordinate(left), formatCoordinate(top), formatCoordinate(size), formatCoordinate(size), fill)
+
+	if p.Board.IsSolidSquare(x, y) {
+		return
+	}
+
+	if cell.IsCircled() {
+		fmt.Fprintf(svg, "<circle cx=\"%s\" cy=\"%s\" r=\"%s\" fill=\"none\" stroke=\"#000000\" stroke-width=\"1\"/>\n", formatCoordinate(left+size/2), formatCoordinate(top+size/2), formatCoordinate(size/2-1))
+	}
+
+	if number != 0 {
+		fmt.Fprintf(svg, "<text x=\"%s\" y=\"%s\" font-family=\"%s\" font-size=\"%s\">%d</text>\n", formatCoordinate(left+size*0.06), formatCoordinate(top+size*0.3), svgFontFamily, formatCoordinate(size*0.28), number)
+	}
+
+	var text string
+
+	switch mode {
+	case RenderSolution:
+		if cell.Answer != EmptySolutionSquare {
+			text = p.Answer(x, y)
+		}
+	case RenderGuesses:
+		if !p.isEmptyGuess(x, y) {
+			text = p.Guess(x, y)
+		}
+	}
+
+	if text == "" {
+		return
+	}
+
+	text = p.textToUTF8(text)
+
+	fontSize := cellFontSize(size, svgLetterCharWidth*float64(len([]rune(text))))
+
+	fmt.Fprintf(svg, "<text x=\"%s\" y=\"%s\" font-family=\"%s\" font-size=\"%s\" text-anchor=\"middle\">", formatCoordinate(left+size/2), formatCoordinate(top+size*0.85), svgFontFamily, formatCoordinate(fontSize))
+	xml.EscapeText(svg, []byte(text))
+	svg.WriteString("</text>\n")
+}
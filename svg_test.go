@@ -0,0 +1,122 @@
+package puz_test
+
+import (
+	"errors"
+	"flag"
+	puz "github.com/cqb13/puz-parser"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata/svg")
+
+func checkGolden(t *testing.T, name string, data []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "svg", name)
+
+	if *updateGolden {
+		err := os.WriteFile(path, data, 0o644)
+		if err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+
+	if string(expected) != string(data) {
+		t.Fatalf("Output does not match %s, run go test -update to regenerate it", path)
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	testCases := []struct {
+		file   string
+		mode   puz.RenderMode
+		golden string
+	}{
+		{"Crossword.puz", puz.RenderBlank, "Crossword-Blank.svg"},
+		{"Crossword.puz", puz.RenderSolution, "Crossword-Solution.svg"},
+		{"Crossword-EXT-Rebus.puz", puz.RenderSolution, "Crossword-EXT-Rebus-Solution.svg"},
+		{"All-Sections-Sorted.puz", puz.RenderGuesses, "All-Sections-Sorted-Guesses.svg"},
+	}
+
+	for _, tc := range testCases {
+		puzzle, err := puz.DecodePuz(loadFile(t, tc.file))
+		if err != nil {
+			t.Fatalf("Failed to decode %s: %v", tc.file, err)
+		}
+
+		svg, err := puz.RenderSVG(puzzle, puz.SVGOptions{Mode: tc.mode})
+		if err != nil {
+			t.Fatalf("Failed to render %s: %v", tc.file, err)
+		}
+
+		checkGolden(t, tc.golden, svg)
+	}
+}
+
+func TestRenderSVGMarkup(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	puzzle.Board[0][0].SetCircled(true)
+	puzzle.Board[1][1].Guess = 'C'
+	puzzle.Board[1][1].SetMarkup(puz.ContentGiven, true)
+	puzzle.Board[2][2].Guess = 'X'
+	puzzle.Board[2][2].SetMarkup(puz.CurrentlyIncorrect, true)
+
+	svg, err := puz.RenderSVG(puzzle, puz.SVGOptions{Mode: puz.RenderGuesses, CellSize: 40})
+	if err != nil {
+		t.Fatalf("Failed to render Crossword.puz: %v", err)
+	}
+
+	checkGolden(t, "Crossword-Markup-Guesses.svg", svg)
+
+	blank, err := puz.RenderSVG(puzzle, puz.SVGOptions{CellSize: 40})
+	if err != nil {
+		t.Fatalf("Failed to render Crossword.puz: %v", err)
+	}
+
+	// markup shading is solver state, only the circle is drawn on a blank grid
+	if strings.Contains(string(blank), "#d9d9d9") || !strings.Contains(string(blank), "<circle") {
+		t.Fatalf("Expected a blank grid with a circle and no shading")
+	}
+}
+
+func TestRenderSVGEmptyAnswer(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	puzzle.Board[1][1].Answer = puz.EmptySolutionSquare
+
+	svg, err := puz.RenderSVG(puzzle, puz.SVGOptions{Mode: puz.RenderSolution})
+	if err != nil {
+		t.Fatalf("Failed to render Crossword.puz: %v", err)
+	}
+
+	if strings.Contains(string(svg), "> </text>") {
+		t.Fatalf("Expected no text for a cell without an answer")
+	}
+}
+
+func TestRenderSVGScrambled(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword-Scrambled.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword-Scrambled.puz: %v", err)
+	}
+
+	_, err = puz.RenderSVG(puzzle, puz.SVGOptions{Mode: puz.RenderSolution})
+	if !errors.Is(err, puz.PuzzleIsScrambledError) {
+		t.Fatalf("Expected PuzzleIsScrambledError, found %v", err)
+	}
+}
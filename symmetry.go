@@ -0,0 +1,183 @@
+package puz
+
+import (
+	"strings"
+)
+
+// Symmetry is a set of flags describing how the solid squares of a board are arranged
+type Symmetry int
+
+const NoSymmetry Symmetry = 0
+
+const (
+	RotationalSymmetry      Symmetry = 1 << iota // Unchanged after a 180° turn, the standard for American crosswords
+	QuarterTurnSymmetry                          // Unchanged after a 90° turn, only possible on square boards
+	MirrorLeftRightSymmetry                      // Unchanged when flipped across the vertical center line
+	MirrorTopBottomSymmetry                      // Unchanged when flipped across the horizontal center line
+	DiagonalSymmetry                             // Unchanged when flipped across the top left to bottom right diagonal, only possible on square boards
+	AntiDiagonalSymmetry                         // Unchanged when flipped across the top right to bottom left diagonal, only possible on square boards
+)
+
+var symmetryStrMap = map[Symmetry]string{
+	RotationalSymmetry:      "Rotational",
+	QuarterTurnSymmetry:     "Quarter Turn",
+	MirrorLeftRightSymmetry: "Mirror Left-Right",
+	MirrorTopBottomSymmetry: "Mirror Top-Bottom",
+	DiagonalSymmetry:        "Diagonal",
+	AntiDiagonalSymmetry:    "Anti-Diagonal",
+}
+
+var symmetries = []Symmetry{
+	RotationalSymmetry,
+	QuarterTurnSymmetry,
+	MirrorLeftRightSymmetry,
+	MirrorTopBottomSymmetry,
+	DiagonalSymmetry,
+	AntiDiagonalSymmetry,
+}
+
+// Has reports if every flag in other is set.
+func (s Symmetry) Has(other Symmetry) bool {
+	return s&other == other
+}
+
+func (s Symmetry) String() string {
+	if s == NoSymmetry {
+		return "None"
+	}
+
+	var names []string
+
+	for _, symmetry := range symmetries {
+		if s.Has(symmetry) {
+			names = append(names, symmetryStrMap[symmetry])
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// requiresSquare reports if the symmetry can only hold on a square board.
+func (s Symmetry) requiresSquare() bool {
+	return s&(QuarterTurnSymmetry|DiagonalSymmetry|AntiDiagonalSymmetry) != 0
+}
+
+// transform returns the position (x, y) is moved to by a single symmetry flag.
+func (b Board) transform(symmetry Symmetry, pos Position) Position {
+	width := b.Width()
+	height := b.Height()
+
+	switch symmetry {
+	case RotationalSymmetry:
+		return Position{width - 1 - pos.X, height - 1 - pos.Y}
+	case QuarterTurnSymmetry:
+		return Position{width - 1 - pos.Y, pos.X}
+	case MirrorLeftRightSymmetry:
+		return Position{width - 1 - pos.X, pos.Y}
+	case MirrorTopBottomSymmetry:
+		return Position{pos.X, height - 1 - pos.Y}
+	case DiagonalSymmetry:
+		return Position{pos.Y, pos.X}
+	case AntiDiagonalSymmetry:
+		return Position{width - 1 - pos.Y, height - 1 - pos.X}
+	}
+
+	return pos
+}
+
+// Symmetry reports every symmetry that holds for the solid squares of the board.
+func (b Board) Symmetry() Symmetry {
+	result := NoSymmetry
+	square := b.Width() == b.Height()
+
+	for _, symmetry := range symmetries {
+		if symmetry.requiresSquare() && !square {
+			continue
+		}
+
+		holds := true
+
+		for y := range b.Height() {
+			for x := range b.Width() {
+				pos := b.transform(symmetry, Position{x, y})
+
+				if b.IsSolidSquare(x, y) != b.IsSolidSquare(pos.X, pos.Y) {
+					holds = false
+					break
+				}
+			}
+
+			if !holds {
+				break
+			}
+		}
+
+		if holds {
+			result |= symmetry
+		}
+	}
+
+	return result
+}
+
+// symmetricCells returns (x, y) and every position it is mapped to by the symmetries in mode.
+func (b Board) symmetricCells(x int, y int, mode Symmetry) []Position {
+	start := Position{x, y}
+	cells := []Position{start}
+	seen := map[Position]bool{start: true}
+
+	for i := 0; i < len(cells); i++ {
+		for _, symmetry := range symmetries {
+			if !mode.Has(symmetry) {
+				continue
+			}
+
+			next := b.transform(symmetry, cells[i])
+			if !seen[next] {
+				seen[next] = true
+				cells = append(cells, next)
+			}
+		}
+	}
+
+	return cells
+}
+
+// SetSolidSymmetric makes the square at (x, y) and every square matched to it by the symmetries in mode solid, or clears them when solid is false.
+//
+// Squares already in the requested state are left as is, so calling it again changes nothing.
+// Squares that change lose their rebus and markup, cleared squares have an empty answer and guess.
+// Returns the positions that were changed.
+func (b Board) SetSolidSymmetric(x int, y int, mode Symmetry, solid bool) ([]Position, error) {
+	if !b.inBounds(x, y) {
+		return nil, OutOfBoundsWriteError
+	}
+
+	if mode.requiresSquare() && b.Width() != b.Height() {
+		return nil, SymmetryRequiresSquareBoardError
+	}
+
+	var changed []Position
+
+	for _, pos := range b.symmetricCells(x, y, mode) {
+		if b.IsSolidSquare(pos.X, pos.Y) == solid {
+			continue
+		}
+
+		cell := &b[pos.Y][pos.X]
+
+		if solid {
+			cell.Answer = SolidSquare
+			cell.Guess = SolidSquare
+		} else {
+			cell.Answer = EmptySolutionSquare
+			cell.Guess = EmptyStateSquare
+		}
+
+		cell.RebusKey = 0
+		cell.Markup = None
+		changed = append(changed, pos)
+	}
+
+	return changed, nil
+}
@@ -0,0 +1,100 @@
+package puz_test
+
+import (
+	puz "github.com/cqb13/puz-parser"
+	"testing"
+)
+
+func TestBoardSymmetry(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected puz.Symmetry
+	}{
+		{"Crossword.puz", puz.RotationalSymmetry | puz.DiagonalSymmetry | puz.AntiDiagonalSymmetry},
+		{"NYT-Nov2193.puz", puz.RotationalSymmetry},
+	}
+
+	for _, tc := range testCases {
+		puzzle, err := puz.DecodePuz(loadFile(t, tc.name))
+		if err != nil {
+			t.Fatalf("Failed to decode %s: %v", tc.name, err)
+		}
+
+		if symmetry := puzzle.Board.Symmetry(); symmetry != tc.expected {
+			t.Errorf("Expected %s for %s, found %s", tc.expected, tc.name, symmetry)
+		}
+	}
+}
+
+func TestBoardSymmetryShapes(t *testing.T) {
+	board := puz.NewBoard(5, 5)
+
+	all := puz.RotationalSymmetry | puz.QuarterTurnSymmetry | puz.MirrorLeftRightSymmetry | puz.MirrorTopBottomSymmetry | puz.DiagonalSymmetry | puz.AntiDiagonalSymmetry
+	if board.Symmetry() != all {
+		t.Fatalf("Expected an empty board to have every symmetry, found %s", board.Symmetry())
+	}
+
+	board[0][1].Answer = puz.SolidSquare
+	if board.Symmetry() != puz.NoSymmetry {
+		t.Fatalf("Expected no symmetry, found %s", board.Symmetry())
+	}
+
+	wide := puz.NewBoard(6, 4)
+	if wide.Symmetry().Has(puz.QuarterTurnSymmetry) || wide.Symmetry().Has(puz.DiagonalSymmetry) {
+		t.Fatalf("Expected a wide board to not have square symmetries, found %s", wide.Symmetry())
+	}
+}
+
+func TestSetSolidSymmetric(t *testing.T) {
+	board := puz.NewBoard(15, 15)
+
+	changed, err := board.SetSolidSymmetric(3, 0, puz.RotationalSymmetry, true)
+	if err != nil {
+		t.Fatalf("Failed to set solid square: %v", err)
+	}
+
+	if len(changed) != 2 || !board.IsSolidSquare(11, 14) {
+		t.Fatalf("Expected the rotated square to be solid, found %v", changed)
+	}
+
+	if !board.Symmetry().Has(puz.RotationalSymmetry) {
+		t.Fatalf("Expected rotational symmetry, found %s", board.Symmetry())
+	}
+
+	changed, _ = board.SetSolidSymmetric(1, 2, puz.QuarterTurnSymmetry, true)
+	if len(changed) != 4 || !board.Symmetry().Has(puz.RotationalSymmetry) {
+		t.Fatalf("Expected 4 squares to change, found %v", changed)
+	}
+
+	// the center square maps to itself
+	changed, _ = board.SetSolidSymmetric(7, 7, puz.RotationalSymmetry, true)
+	if len(changed) != 1 {
+		t.Fatalf("Expected only the center to change, found %v", changed)
+	}
+
+	// setting the same state again changes nothing
+	changed, _ = board.SetSolidSymmetric(11, 14, puz.RotationalSymmetry, true)
+	if len(changed) != 0 || !board.IsSolidSquare(3, 0) {
+		t.Fatalf("Expected no squares to change, found %v", changed)
+	}
+
+	changed, _ = board.SetSolidSymmetric(11, 14, puz.RotationalSymmetry, false)
+	if len(changed) != 2 || board.IsSolidSquare(3, 0) || board[0][3].Guess != puz.EmptyStateSquare {
+		t.Fatalf("Expected both squares to be cleared, found %v", changed)
+	}
+
+	board[0][0].Markup = puz.SquareCircled
+	board.SetSolidSymmetric(0, 0, puz.RotationalSymmetry, true)
+	if board[0][0].Markup != puz.None {
+		t.Fatalf("Expected markup to be cleared, found %v", board[0][0].Markup)
+	}
+
+	wide := puz.NewBoard(6, 4)
+	if _, err := wide.SetSolidSymmetric(0, 0, puz.DiagonalSymmetry, true); err != puz.SymmetryRequiresSquareBoardError {
+		t.Fatalf("Expected SymmetryRequiresSquareBoardError, found %v", err)
+	}
+
+	if _, err := wide.SetSolidSymmetric(6, 0, puz.RotationalSymmetry, true); err != puz.OutOfBoundsWriteError {
+		t.Fatalf("Expected OutOfBoundsWriteError, found %v", err)
+	}
+}
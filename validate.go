@@ -0,0 +1,273 @@
+package puz
+
+import (
+	"fmt"
+)
+
+// Severity is how serious an Issue is
+type Severity int
+
+const (
+	SeverityWarning Severity = iota // Breaks a construction convention, the puzzle still works
+	SeverityError                   // The puzzle is broken or will not encode correctly
+)
+
+var severityStrMap = map[Severity]string{
+	SeverityWarning: "Warning",
+	SeverityError:   "Error",
+}
+
+func (s Severity) String() string {
+	return severityStrMap[s]
+}
+
+// IssueKind identifies the problem reported by an Issue
+type IssueKind int
+
+const (
+	UncheckedSquareIssue   IssueKind = iota // A letter is only part of one word
+	ShortWordIssue                          // A word has fewer than 3 letters
+	DisconnectedGridIssue                   // A group of white squares is not connected to the rest of the grid
+	MissingClueIssue                        // A word in the grid has no clue
+	ExtraClueIssue                          // A clue does not match a word in the grid
+	MisplacedClueIssue                      // A clue number or position disagrees with the grid
+	EmptyAnswerIssue                        // A white square has no answer
+	ClueCountMismatchIssue                  // The expected clue count does not match the clue list
+)
+
+var issueKindStrMap = map[IssueKind]string{
+	UncheckedSquareIssue:   "Unchecked Square",
+	ShortWordIssue:         "Short Word",
+	DisconnectedGridIssue:  "Disconnected Grid",
+	MissingClueIssue:       "Missing Clue",
+	ExtraClueIssue:         "Extra Clue",
+	MisplacedClueIssue:     "Misplaced Clue",
+	EmptyAnswerIssue:       "Empty Answer",
+	ClueCountMismatchIssue: "Clue Count Mismatch",
+}
+
+func (k IssueKind) String() string {
+	return issueKindStrMap[k]
+}
+
+// Issue is a problem found by Validate
+type Issue struct {
+	Kind     IssueKind
+	Severity Severity
+	Cells    []Position // The affected cells, empty when the issue is not tied to the grid
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s: %s", i.Severity.String(), i.Kind.String(), i.Message)
+}
+
+// Validate checks the puzzle for construction problems and returns every issue found.
+//
+// Unchecked squares, short words, and disconnected grids are warnings,
+// clue mismatches and empty answers are errors.
+func (p *Puzzle) Validate() []Issue {
+	var issues []Issue
+
+	issues = append(issues, p.validateEmptyAnswers()...)
+	issues = append(issues, p.validateUncheckedSquares()...)
+	issues = append(issues, p.validateShortWords()...)
+	issues = append(issues, p.validateConnected()...)
+	issues = append(issues, p.validateClues()...)
+
+	if int(p.expectedClues) != len(p.clues) {
+		issues = append(issues, Issue{
+			ClueCountMismatchIssue,
+			SeverityError,
+			nil,
+			fmt.Sprintf("expected %d clues, found %d", p.expectedClues, len(p.clues)),
+		})
+	}
+
+	return issues
+}
+
+func (p *Puzzle) validateEmptyAnswers() []Issue {
+	var issues []Issue
+
+	for _, pos := range p.Board.cells() {
+		if p.Board.IsSolidSquare(pos.X, pos.Y) {
+			continue
+		}
+
+		answer := p.Board[pos.Y][pos.X].Answer
+		if answer == EmptySolutionSquare || answer == 0x00 {
+			issues = append(issues, Issue{
+				EmptyAnswerIssue,
+				SeverityError,
+				[]Position{pos},
+				fmt.Sprintf("%s has no answer", formatPosition(pos.X, pos.Y)),
+			})
+		}
+	}
+
+	return issues
+}
+
+func (p *Puzzle) validateUncheckedSquares() []Issue {
+	var issues []Issue
+
+	// a square is in at most one across and one down word
+	words := make(map[Position]int)
+	for _, word := range p.Board.GetWords() {
+		for _, pos := range p.wordCells(word) {
+			words[pos]++
+		}
+	}
+
+	for _, pos := range p.Board.cells() {
+		if p.Board.IsSolidSquare(pos.X, pos.Y) || words[pos] == 2 {
+			continue
+		}
+
+		issues = append(issues, Issue{
+			UncheckedSquareIssue,
+			SeverityWarning,
+			[]Position{pos},
+			fmt.Sprintf("%s is not part of both an across and a down word", formatPosition(pos.X, pos.Y)),
+		})
+	}
+
+	return issues
+}
+
+func (p *Puzzle) validateShortWords() []Issue {
+	var issues []Issue
+
+	for _, word := range p.Board.GetWords() {
+		cells := p.wordCells(word)
+
+		if len(cells) < 3 {
+			issues = append(issues, Issue{
+				ShortWordIssue,
+				SeverityWarning,
+				cells,
+				fmt.Sprintf("%d %s has %d letters", word.Num, directionName(word.Direction), len(cells)),
+			})
+		}
+	}
+
+	return issues
+}
+
+// validateConnected reports every group of white squares that is not part of the largest group.
+func (p *Puzzle) validateConnected() []Issue {
+	regions := p.Board.whiteRegions()
+
+	largest := 0
+	for i, region := range regions {
+		if len(region) > len(regions[largest]) {
+			largest = i
+		}
+	}
+
+	var issues []Issue
+
+	for i, region := range regions {
+		if i == largest {
+			continue
+		}
+
+		issues = append(issues, Issue{
+			DisconnectedGridIssue,
+			SeverityWarning,
+			region,
+			fmt.Sprintf("%d squares starting at %s are not connected to the rest of the grid", len(region), formatPosition(region[0].X, region[0].Y)),
+		})
+	}
+
+	return issues
+}
+
+// validateClues compares the clue list with the words in the grid.
+//
+// Clues are matched to words by position and direction first, then by number and direction.
+func (p *Puzzle) validateClues() []Issue {
+	var issues []Issue
+
+	words := p.Board.GetWords()
+	matchedWords := make([]bool, len(words))
+	matchedClues := make([]bool, len(p.clues))
+
+	for i, clue := range p.clues {
+		for j, word := range words {
+			if matchedWords[j] || word.Direction != clue.Direction || word.StartX != clue.StartX || word.StartY != clue.StartY {
+				continue
+			}
+
+			matchedWords[j] = true
+			matchedClues[i] = true
+
+			if word.Num != clue.Num {
+				issues = append(issues, Issue{
+					MisplacedClueIssue,
+					SeverityError,
+					p.wordCells(word),
+					fmt.Sprintf("clue %d %s is numbered %d in the grid", clue.Num, directionName(clue.Direction), word.Num),
+				})
+			}
+
+			break
+		}
+	}
+
+	for i, clue := range p.clues {
+		if matchedClues[i] {
+			continue
+		}
+
+		misplaced := false
+
+		for j, word := range words {
+			if matchedWords[j] || word.Direction != clue.Direction || word.Num != clue.Num {
+				continue
+			}
+
+			matchedWords[j] = true
+			misplaced = true
+
+			issues = append(issues, Issue{
+				MisplacedClueIssue,
+				SeverityError,
+				p.wordCells(word),
+				fmt.Sprintf("clue %d %s is at %s, the grid word starts at %s", clue.Num, directionName(clue.Direction), formatPosition(clue.StartX, clue.StartY), formatPosition(word.StartX, word.StartY)),
+			})
+
+			break
+		}
+
+		if !misplaced {
+			issues = append(issues, Issue{
+				ExtraClueIssue,
+				SeverityError,
+				nil,
+				fmt.Sprintf("clue %d %s does not match a word in the grid", clue.Num, directionName(clue.Direction)),
+			})
+		}
+	}
+
+	for j, word := range words {
+		if matchedWords[j] {
+			continue
+		}
+
+		issues = append(issues, Issue{
+			MissingClueIssue,
+			SeverityError,
+			p.wordCells(word),
+			fmt.Sprintf("%d %s has no clue", word.Num, directionName(word.Direction)),
+		})
+	}
+
+	return issues
+}
+
+// formatPosition formats (x, y) for issue messages.
+func formatPosition(x int, y int) string {
+	return fmt.Sprintf("x: %d y: %d", x, y)
+}
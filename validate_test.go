@@ -0,0 +1,113 @@
+package puz_test
+
+import (
+	puz "github.com/cqb13/puz-parser"
+	"slices"
+	"testing"
+)
+
+func countIssues(issues []puz.Issue, kind puz.IssueKind) int {
+	count := 0
+
+	for _, issue := range issues {
+		if issue.Kind == kind {
+			count++
+		}
+	}
+
+	return count
+}
+
+func TestValidateCleanPuzzles(t *testing.T) {
+	testCases := []string{
+		"Crossword.puz",
+		"NYT-Nov2193.puz",
+		"washpost.puz",
+	}
+
+	for _, name := range testCases {
+		puzzle, err := puz.DecodePuz(loadFile(t, name))
+		if err != nil {
+			t.Fatalf("Failed to decode %s: %v", name, err)
+		}
+
+		if issues := puzzle.Validate(); len(issues) != 0 {
+			t.Errorf("Expected no issues for %s, found %v", name, issues)
+		}
+	}
+}
+
+func TestValidateGrid(t *testing.T) {
+	board, err := puz.NewBoardFromArr([][]byte{
+		[]byte("AB.CD"),
+		[]byte("AB.CD"),
+		[]byte("....."),
+		[]byte("EF H."),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create board: %v", err)
+	}
+
+	puzzle := puz.NewPuzzleFromBoard(board)
+	issues := puzzle.Validate()
+
+	expected := map[puz.IssueKind]int{
+		puz.EmptyAnswerIssue:      1,
+		puz.UncheckedSquareIssue:  4,
+		puz.ShortWordIssue:        8,
+		puz.DisconnectedGridIssue: 2,
+		puz.MissingClueIssue:      9,
+	}
+
+	for kind, count := range expected {
+		if found := countIssues(issues, kind); found != count {
+			t.Errorf("Expected %d %s issues, found %d", count, kind, found)
+		}
+	}
+
+	for _, issue := range issues {
+		if issue.Kind == puz.EmptyAnswerIssue && !slices.Equal(issue.Cells, []puz.Position{{X: 2, Y: 3}}) {
+			t.Errorf("Expected the empty answer at x: 2 y: 3, found %v", issue.Cells)
+		}
+
+		if issue.Kind == puz.UncheckedSquareIssue && issue.Severity != puz.SeverityWarning {
+			t.Errorf("Expected unchecked squares to be warnings")
+		}
+
+		if issue.Kind == puz.MissingClueIssue && issue.Severity != puz.SeverityError {
+			t.Errorf("Expected missing clues to be errors")
+		}
+	}
+}
+
+func TestValidateClues(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	clues := puzzle.Clues()
+
+	// renumber 1 across, move 2 down, and drop the last clue
+	clues[0].Num = 10
+	clues[2].StartY = 2
+	extra := puz.NewClue("Not in the grid", 12, 4, 4, puz.Across)
+	clues = append(clues[:len(clues)-1], extra)
+
+	puzzle.SetClues(clues)
+
+	issues := puzzle.Validate()
+
+	expected := map[puz.IssueKind]int{
+		puz.MisplacedClueIssue:     2,
+		puz.ExtraClueIssue:         1,
+		puz.MissingClueIssue:       1,
+		puz.ClueCountMismatchIssue: 0,
+	}
+
+	for kind, count := range expected {
+		if found := countIssues(issues, kind); found != count {
+			t.Errorf("Expected %d %s issues, found %d: %v", count, kind, found, issues)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package puz
+
+import (
+	"math/bits"
+	"slices"
+	"strings"
+)
+
+// PatternWildcard matches any character in a WordIndex pattern
+const PatternWildcard = '?'
+
+// A WordIndex holds scored words by length with the set of words that have each letter at each position, for fast pattern matching.
+type WordIndex struct {
+	lengths map[int]*fillIndex
+	size    int
+}
+
+// fillIndex holds the words of one length, highest score first, as bitsets per position and letter.
+type fillIndex struct {
+	words   []FillWord
+	letters [][256][]uint64 // letters[position][letter] is the set of words with letter at position
+	all     []uint64
+}
+
+// NewWordIndex indexes words for Match and Autofill.
+// Words are uppercased, empty words and words with anything other than letters and digits are skipped, and a word listed more than once keeps its first score.
+// Words with equal scores keep their order from words.
+func NewWordIndex(words []FillWord) *WordIndex {
+	byLength := make(map[int][]FillWord)
+	seen := make(map[string]bool)
+
+	for _, word := range words {
+		text := strings.ToUpper(word.Word)
+		if text == "" || !fillableWord(text) || seen[text] {
+			continue
+		}
+
+		seen[text] = true
+		byLength[len(text)] = append(byLength[len(text)], FillWord{text, word.Score})
+	}
+
+	index := WordIndex{
+		make(map[int]*fillIndex),
+		len(seen),
+	}
+
+	for length, list := range byLength {
+		slices.SortStableFunc(list, func(a FillWord, b FillWord) int {
+			return b.Score - a.Score
+		})
+
+		index.lengths[length] = newFillIndex(list, length)
+	}
+
+	return &index
+}
+
+// fillableWord returns true if every character in word is an uppercase letter or digit, so it can not place a marker like SolidSquare or EmptySolutionSquare in the grid.
+func fillableWord(word string) bool {
+	for _, c := range []byte(word) {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newFillIndex(words []FillWord, length int) *fillIndex {
+	size := (len(words) + 63) / 64
+
+	index := fillIndex{
+		words,
+		make([][256][]uint64, length),
+		make([]uint64, size),
+	}
+
+	for i, word := range words {
+		index.all[i/64] |= 1 << (i % 64)
+
+		for pos := range length {
+			letter := word.Word[pos]
+
+			if index.letters[pos][letter] == nil {
+				index.letters[pos][letter] = make([]uint64, size)
+			}
+
+			index.letters[pos][letter][i/64] |= 1 << (i % 64)
+		}
+	}
+
+	return &index
+}
+
+// Len returns the number of words in the index.
+func (w *WordIndex) Len() int {
+	return w.size
+}
+
+// Match returns the words that fit pattern, highest score first.
+//
+// Each character in pattern is a letter the word must have at that position or PatternWildcard for any character.
+// Letters in pattern are uppercased before matching.
+func (w *WordIndex) Match(pattern string) []FillWord {
+	pattern = strings.ToUpper(pattern)
+
+	index, set := w.lookup(len(pattern), func(i int) (byte, bool) {
+		return pattern[i], pattern[i] != PatternWildcard
+	})
+
+	var matches []FillWord
+
+	for block, word := range set {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			word &^= 1 << bit
+
+			matches = append(matches, index.words[block*64+bit])
+		}
+	}
+
+	return matches
+}
+
+// lookup returns the words of length that have the letter from letterAt at every position where it reports true.
+// The set is nil if no word matches.
+func (w *WordIndex) lookup(length int, letterAt func(i int) (byte, bool)) (*fillIndex, []uint64) {
+	index, ok := w.lengths[length]
+	if !ok {
+		return nil, nil
+	}
+
+	set := slices.Clone(index.all)
+
+	for i := range length {
+		letter, known := letterAt(i)
+		if !known {
+			continue
+		}
+
+		letters := index.letters[i][letter]
+		if letters == nil {
+			return index, nil
+		}
+
+		for j := range set {
+			set[j] &= letters[j]
+		}
+	}
+
+	return index, set
+}
+
+func countSet(set []uint64) int {
+	count := 0
+
+	for _, word := range set {
+		count += bits.OnesCount64(word)
+	}
+
+	return count
+}
+
+// firstInSet returns the index of the first word in set, -1 if set is empty.
+func firstInSet(set []uint64) int {
+	for block, word := range set {
+		if word != 0 {
+			return block*64 + bits.TrailingZeros64(word)
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,31 @@
+package wordlist
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	InvalidScoreError = errors.New("Score is not a whole number")
+	EmptyWordError    = errors.New("Word has no letters or digits")
+)
+
+// Invalid Line
+type InvalidLineError struct {
+	line int
+	text string
+	err  error
+}
+
+func (e *InvalidLineError) Error() string {
+	return fmt.Sprintf("Invalid word list entry on line %d %q: %v", e.line, e.text, e.err)
+}
+
+// Line returns the line number of the invalid entry, starting at 1
+func (e *InvalidLineError) Line() int {
+	return e.line
+}
+
+func (e *InvalidLineError) Unwrap() error {
+	return e.err
+}
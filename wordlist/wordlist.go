@@ -0,0 +1,218 @@
+// Package wordlist loads scored word lists and matches words against crossword patterns.
+package wordlist
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	puz "github.com/cqb13/puz-parser"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultScore is the score given to words listed without one
+const DefaultScore = 50
+
+// Wildcard matches any letter in a pattern
+const Wildcard = puz.PatternWildcard
+
+// An Entry is a word and its score
+type Entry = puz.FillWord
+
+// A List is a set of scored words.
+type List struct {
+	scores map[string]int
+	index  *puz.WordIndex // built on the first match after a change
+}
+
+// New returns an empty List
+func New() *List {
+	return &List{
+		make(map[string]int),
+		nil,
+	}
+}
+
+// Load reads a word list in the WORD;score format, one entry per line.
+//
+// Words are uppercased and anything other than letters and digits is removed, so "ice cream;40" becomes ICECREAM.
+// Words without a score get DefaultScore, blank lines and lines starting with # are skipped.
+// A word listed more than once keeps its last score.
+// Returns an InvalidLineError for entries with a bad score or no letters.
+func Load(r io.Reader) (*List, error) {
+	list := New()
+	scanner := bufio.NewScanner(r)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		word, score, err := parseEntry(text)
+		if err != nil {
+			return nil, &InvalidLineError{
+				line,
+				text,
+				err,
+			}
+		}
+
+		list.Add(word, score)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Failed to read word list: %w", err)
+	}
+
+	return list, nil
+}
+
+func parseEntry(text string) (string, int, error) {
+	word := text
+	score := DefaultScore
+
+	if i := strings.LastIndexByte(text, ';'); i != -1 {
+		word = text[:i]
+
+		parsed, err := strconv.Atoi(strings.TrimSpace(text[i+1:]))
+		if err != nil {
+			return "", 0, InvalidScoreError
+		}
+
+		score = parsed
+	}
+
+	if Normalize(word) == "" {
+		return "", 0, EmptyWordError
+	}
+
+	return word, score, nil
+}
+
+// Normalize uppercases word and removes anything that is not a letter or digit.
+func Normalize(word string) string {
+	var out strings.Builder
+
+	for _, ch := range []byte(word) {
+		if ch >= 'a' && ch <= 'z' {
+			ch -= 'a' - 'A'
+		}
+
+		if (ch >= 'A' && ch <= 'Z') || (ch >= '0' && ch <= '9') {
+			out.WriteByte(ch)
+		}
+	}
+
+	return out.String()
+}
+
+// Merge returns a new List with the words from every list, scores from later lists override earlier ones.
+func Merge(lists ...*List) *List {
+	merged := New()
+
+	for _, list := range lists {
+		for word, score := range list.scores {
+			merged.scores[word] = score
+		}
+	}
+
+	return merged
+}
+
+// Add adds word to the list, replacing its score if it is already listed.
+// Words with no letters or digits are ignored.
+func (l *List) Add(word string, score int) {
+	word = Normalize(word)
+	if word == "" {
+		return
+	}
+
+	l.scores[word] = score
+	l.index = nil
+}
+
+// Remove removes word from the list.
+func (l *List) Remove(word string) {
+	delete(l.scores, Normalize(word))
+	l.index = nil
+}
+
+// Score returns the score for word, the bool is false if the word is not listed.
+func (l *List) Score(word string) (int, bool) {
+	score, ok := l.scores[Normalize(word)]
+
+	return score, ok
+}
+
+// Len returns the number of words in the list
+func (l *List) Len() int {
+	return len(l.scores)
+}
+
+// Entries returns every word in the list, highest score first and alphabetically for equal scores.
+func (l *List) Entries() []Entry {
+	entries := make([]Entry, 0, len(l.scores))
+
+	for word, score := range l.scores {
+		entries = append(entries, Entry{Word: word, Score: score})
+	}
+
+	slices.SortFunc(entries, compareEntries)
+
+	return entries
+}
+
+func compareEntries(a Entry, b Entry) int {
+	if a.Score != b.Score {
+		return b.Score - a.Score
+	}
+
+	return cmp.Compare(a.Word, b.Word)
+}
+
+// Match returns the words that fit pattern, highest score first and alphabetically for equal scores.
+//
+// Each character in pattern is a letter the word must have at that position or Wildcard for any character.
+// Letters in pattern are matched case insensitively.
+func (l *List) Match(pattern string) []Entry {
+	return l.Index().Match(pattern)
+}
+
+// Index returns the list indexed for pattern matching, it can be passed to puz.Autofill through AutofillOptions.Index.
+// The index is built once and reused until the list changes.
+func (l *List) Index() *puz.WordIndex {
+	if l.index == nil {
+		l.index = puz.NewWordIndex(l.Entries())
+	}
+
+	return l.index
+}
+
+// A LowScoringWord is an answer in a puzzle that scored below a threshold
+type LowScoringWord struct {
+	Word   puz.Word // The word from the puzzle, with rebus cells expanded
+	Score  int      // The score from the list, 0 if the word is not listed
+	Listed bool     // Reports if the word is in the list
+}
+
+// LowScoringWords returns the answers in puzzle that score below threshold, in the order of puzzle.GetWords.
+// Answers that are not in the list are reported with a score of 0.
+func (l *List) LowScoringWords(puzzle *puz.Puzzle, threshold int) []LowScoringWord {
+	var low []LowScoringWord
+
+	for _, word := range puzzle.GetWords() {
+		score, listed := l.Score(word.Word)
+
+		if score < threshold {
+			low = append(low, LowScoringWord{word, score, listed})
+		}
+	}
+
+	return low
+}
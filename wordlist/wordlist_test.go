@@ -0,0 +1,147 @@
+package wordlist_test
+
+import (
+	"errors"
+	puz "github.com/cqb13/puz-parser"
+	"github.com/cqb13/puz-parser/wordlist"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testList = `# test list
+BASS;60
+ACHED;40
+stone;55
+ice cream;30
+HORSE
+SHORE;20
+SHORE;70
+`
+
+func loadList(t *testing.T, text string) *wordlist.List {
+	t.Helper()
+
+	list, err := wordlist.Load(strings.NewReader(text))
+	if err != nil {
+		t.Fatalf("Failed to load word list: %v", err)
+	}
+
+	return list
+}
+
+func TestLoad(t *testing.T) {
+	list := loadList(t, testList)
+
+	if list.Len() != 6 {
+		t.Fatalf("Expected 6 words, found %d", list.Len())
+	}
+
+	expected := map[string]int{"STONE": 55, "ICECREAM": 30, "HORSE": wordlist.DefaultScore, "SHORE": 70}
+	for word, score := range expected {
+		found, ok := list.Score(word)
+		if !ok || found != score {
+			t.Fatalf("Expected %s to score %d, found %d", word, score, found)
+		}
+	}
+}
+
+func TestLoadInvalidLine(t *testing.T) {
+	_, err := wordlist.Load(strings.NewReader("BASS;60\nACHED;high\n"))
+
+	var lineErr *wordlist.InvalidLineError
+	if !errors.As(err, &lineErr) || lineErr.Line() != 2 || !errors.Is(err, wordlist.InvalidScoreError) {
+		t.Fatalf("Expected an InvalidScoreError on line 2, found %v", err)
+	}
+
+	_, err = wordlist.Load(strings.NewReader("--;60\n"))
+	if !errors.Is(err, wordlist.EmptyWordError) {
+		t.Fatalf("Expected EmptyWordError, found %v", err)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	list := loadList(t, testList)
+
+	matches := list.Match("?o??e")
+	expected := []wordlist.Entry{{Word: "HORSE", Score: 50}}
+	if !slices.Equal(matches, expected) {
+		t.Fatalf("Expected %v, found %v", expected, matches)
+	}
+
+	matches = list.Match("?????")
+	expected = []wordlist.Entry{{Word: "SHORE", Score: 70}, {Word: "STONE", Score: 55}, {Word: "HORSE", Score: 50}, {Word: "ACHED", Score: 40}}
+	if !slices.Equal(matches, expected) {
+		t.Fatalf("Expected %v, found %v", expected, matches)
+	}
+
+	if matches := list.Match("Z????"); matches != nil {
+		t.Fatalf("Expected no matches, found %v", matches)
+	}
+
+	list.Add("chore", 90)
+	matches = list.Match("?HO?E")
+	expected = []wordlist.Entry{{Word: "CHORE", Score: 90}, {Word: "SHORE", Score: 70}}
+	if !slices.Equal(matches, expected) {
+		t.Fatalf("Expected %v after adding a word, found %v", expected, matches)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := loadList(t, testList)
+	overrides := loadList(t, "STONE;10\nREED;45\n")
+
+	merged := wordlist.Merge(base, overrides)
+
+	if merged.Len() != 7 {
+		t.Fatalf("Expected 7 words, found %d", merged.Len())
+	}
+
+	if score, _ := merged.Score("STONE"); score != 10 {
+		t.Fatalf("Expected the override score 10 for STONE, found %d", score)
+	}
+
+	if score, _ := base.Score("STONE"); score != 55 {
+		t.Fatalf("Expected the base list to be unchanged, found %d", score)
+	}
+
+	index := merged.Index()
+	if index.Len() != 7 || index != merged.Index() {
+		t.Fatalf("Expected a cached index of 7 words, found %d", index.Len())
+	}
+
+	if matches := index.Match("s?o?e"); len(matches) != 2 || matches[0] != (puz.FillWord{Word: "SHORE", Score: 70}) {
+		t.Fatalf("Found unexpected matches %v", matches)
+	}
+}
+
+func TestLowScoringWords(t *testing.T) {
+	data, err := os.ReadFile("../testdata/Crossword.puz")
+	if err != nil {
+		t.Fatalf("Failed to read Crossword.puz: %v", err)
+	}
+
+	puzzle, err := puz.DecodePuz(data)
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	list := loadList(t, testList)
+
+	low := list.LowScoringWords(puzzle, 45)
+
+	var found []string
+	for _, word := range low {
+		found = append(found, word.Word.Word)
+
+		if word.Word.Word == "ACHED" && (!word.Listed || word.Score != 40) {
+			t.Fatalf("Expected ACHED to be listed with a score of 40, found %v", word)
+		}
+	}
+
+	// every answer except BASS, STONE, HORSE, and SHORE is missing from the list or scores below 45
+	if len(low) != len(puzzle.GetWords())-4 || !slices.Contains(found, "ACHED") || slices.Contains(found, "STONE") {
+		t.Fatalf("Found unexpected low scoring words %v", found)
+	}
+}
@@ -0,0 +1,356 @@
+package puz
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const xdBlock byte = '#'
+const xdEmpty byte = '.'
+const xdOmitted byte = '_'
+
+var xdCluePattern = regexp.MustCompile(`^([AD])(\d+)\.\s*(.*)$`)
+
+type xdClue struct {
+	text   string // The clue text
+	answer string // The answer given after ~, may be empty
+}
+
+// DecodeXd parses a puzzle in the xd format and returns Puzzle.
+//
+// The Title, Author, Copyright, Notes, Rebus, and Special headers are used, other headers are reported in the returned warnings.
+// Rebus cells use symbols from the Rebus header (e.g. "Rebus: 1=HEART 2=CLUB") and lowercase letters are circled when the Special header is circle.
+// Clues in the form "A1. Clue ~ ANSWER" are matched to the grid numbering from Board.GetWords.
+func DecodeXd(data []byte) (*Puzzle, []ConversionWarning, error) {
+	var warnings conversionWarnings
+
+	text := strings.TrimPrefix(string(data), string(utf8BOM))
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	i := 0
+	skipBlank := func() {
+		for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			i++
+		}
+	}
+
+	// headers
+	headers := map[string]string{}
+	headerLines := map[string]int{}
+	skipBlank()
+	for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+		key, value, ok := strings.Cut(lines[i], ":")
+		if !ok {
+			return nil, nil, &TextParseError{i + 1, InvalidXdHeaderError}
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		headers[key] = strings.TrimSpace(value)
+		headerLines[key] = i + 1
+
+		switch key {
+		case "title", "author", "copyright", "notes", "rebus", "special":
+		default:
+			warnings.add("headers", fmt.Sprintf("the %q header is not supported and was dropped", key))
+		}
+	}
+
+	// grid
+	var grid []textLine
+	skipBlank()
+	for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+		grid = append(grid, textLine{i + 1, strings.TrimSpace(lines[i])})
+	}
+
+	if len(grid) == 0 {
+		return nil, nil, &TextParseError{i + 1, EmptyTextGridError}
+	}
+
+	width := len(grid[0].text)
+	height := len(grid)
+	if width > 255 || height > 255 {
+		return nil, nil, &TextParseError{grid[0].num, TextGridSizeMismatchError}
+	}
+
+	for _, line := range grid {
+		if len(line.text) != width {
+			return nil, nil, &TextParseError{line.num, TextGridSizeMismatchError}
+		}
+	}
+
+	// clues, with any text after them used as notes
+	clues := map[Direction]map[int]xdClue{Across: {}, Down: {}}
+	var notes []string
+	skipBlank()
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if len(notes) > 0 || (line != "" && !xdCluePattern.MatchString(line)) {
+			notes = append(notes, strings.TrimRight(lines[i], " \t"))
+			continue
+		}
+
+		if line == "" {
+			continue
+		}
+
+		match := xdCluePattern.FindStringSubmatch(line)
+		num, _ := strconv.Atoi(match[2])
+
+		dir := Direction(Across)
+		if match[1] == "D" {
+			dir = Down
+		}
+
+		if _, ok := clues[dir][num]; ok {
+			return nil, nil, &TextParseError{i + 1, DuplicateXdClueError}
+		}
+
+		// the answer follows the last ~, the clue may be empty
+		clueText, answer := match[3], ""
+		if i := strings.LastIndex(clueText, "~"); i != -1 {
+			clueText, answer = clueText[:i], clueText[i+1:]
+		}
+
+		clues[dir][num] = xdClue{strings.TrimSpace(clueText), strings.TrimSpace(answer)}
+	}
+
+	rebuses, err := parseXdRebus(headers["rebus"])
+	if err != nil {
+		return nil, nil, &TextParseError{headerLines["rebus"], err}
+	}
+
+	circles := false
+	switch strings.ToLower(headers["special"]) {
+	case "":
+	case "circle", "circled":
+		circles = true
+	default:
+		warnings.add("special", fmt.Sprintf("%s special cells are not supported and were not marked", headers["special"]))
+	}
+
+	puzzle := NewPuzzle(uint8(width), uint8(height))
+
+	texts := []string{headers["title"], headers["author"], headers["copyright"], headers["notes"], strings.Join(notes, "\n")}
+	for _, list := range clues {
+		for _, clue := range list {
+			texts = append(texts, clue.text)
+		}
+	}
+	puzzle.setTextVersion(texts)
+
+	for y, line := range grid {
+		for x := range width {
+			ch := line.text[x]
+
+			switch {
+			case ch == xdBlock || ch == xdOmitted:
+				if ch == xdOmitted {
+					warnings.add("omitted cells", "cells outside of the puzzle were converted to solid squares")
+				}
+
+				puzzle.Board[y][x].Answer = SolidSquare
+				puzzle.Board[y][x].Guess = SolidSquare
+			case ch == xdEmpty:
+				warnings.add("solution", "some cells have no answer and were left empty")
+			case rebuses[ch] != "":
+				err = puzzle.SetRebus(x, y, importText(puzzle, rebuses[ch], "rebus", &warnings))
+				if err != nil {
+					return nil, nil, &TextParseError{line.num, err}
+				}
+			case ch >= 'a' && ch <= 'z':
+				if circles {
+					puzzle.Board[y][x].SetCircled(true)
+					puzzle.AddExtraSection(MarkupBoardSection)
+				}
+
+				puzzle.Board[y][x].Answer = ch - ('a' - 'A')
+			default:
+				puzzle.Board[y][x].Answer = ch
+			}
+		}
+	}
+
+	puzzle.Title = importText(puzzle, headers["title"], "title", &warnings)
+	puzzle.Author = importText(puzzle, headers["author"], "author", &warnings)
+	puzzle.Copyright = importText(puzzle, headers["copyright"], "copyright", &warnings)
+
+	noteParts := []string{headers["notes"], strings.TrimSpace(strings.Join(notes, "\n"))}
+	if noteParts[0] == "" || noteParts[1] == "" {
+		puzzle.Notes = importText(puzzle, noteParts[0]+noteParts[1], "notes", &warnings)
+	} else {
+		puzzle.Notes = importText(puzzle, strings.Join(noteParts, "\n"), "notes", &warnings)
+	}
+
+	var puzzleClues Clues
+	for _, word := range puzzle.Board.GetWords() {
+		clue, ok := clues[word.Direction][word.Num]
+		if !ok {
+			warnings.add("clues", fmt.Sprintf("no clue was found for %d %s and an empty clue was added", word.Num, directionName(word.Direction)))
+		}
+		delete(clues[word.Direction], word.Num)
+
+		if clue.answer != "" && !strings.EqualFold(clue.answer, puzzle.textToUTF8(puzzle.WordAnswer(word))) {
+			warnings.add("answers", fmt.Sprintf("the answer for %d %s does not match the grid, the grid was used", word.Num, directionName(word.Direction)))
+		}
+
+		puzzleClues = append(puzzleClues, NewClue(importText(puzzle, clue.text, "clues", &warnings), word.Num, word.StartX, word.StartY, word.Direction))
+	}
+
+	if len(clues[Across]) > 0 || len(clues[Down]) > 0 {
+		warnings.add("clues", "clues that do not match a word in the grid were dropped")
+	}
+
+	puzzle.SetClues(puzzleClues)
+
+	return puzzle, warnings, nil
+}
+
+// EncodeXd encodes the puzzle in the xd format.
+//
+// Rebus cells are written with the Rebus header and circled cells as lowercase letters with "Special: circle".
+// Data xd can not hold, such as player guesses and the timer, is reported in the returned warnings.
+// Returns PuzzleIsScrambledError if the puzzle is scrambled because the answers can not be exported.
+func EncodeXd(puzzle *Puzzle) ([]byte, []ConversionWarning, error) {
+	var warnings conversionWarnings
+
+	if puzzle.Scrambled() {
+		return nil, nil, PuzzleIsScrambledError
+	}
+
+	if puzzle.HasExtraSection(TimerSection) {
+		warnings.add("timer", "the timer is not supported by xd and was dropped")
+	}
+
+	if puzzle.PuzzleType == Diagramless {
+		warnings.add("diagramless", "the diagramless puzzle type is not supported by xd and was dropped")
+	}
+
+	symbols := newRebusSymbolTable(puzzle.Board)
+	var rebusHeader []string
+	hasCircles := false
+
+	var grid strings.Builder
+	for y := range puzzle.Board.Height() {
+		for x := range puzzle.Board.Width() {
+			cell := puzzle.Board[y][x]
+			ch := cell.Answer
+
+			if cell.Guess != EmptyStateSquare && !puzzle.Board.IsSolidSquare(x, y) {
+				warnings.add("guesses", "player guesses are not supported by xd and were dropped")
+			}
+
+			if cell.Markup&^SquareCircled != 0 {
+				warnings.add("markup", "incorrect and given markup is not supported by xd and was dropped")
+			}
+
+			circled := cell.IsCircled()
+
+			switch {
+			case puzzle.Board.IsSolidSquare(x, y):
+				ch = xdBlock
+			case cell.RebusKey != 0:
+				entry, ok := rebusEntryByKey(puzzle.Extras.RebusTable, int(cell.RebusKey))
+				if !ok {
+					warnings.add("rebus", fmt.Sprintf("rebus at %d,%d is missing from the rebus table and was dropped", x, y))
+					break
+				}
+
+				symbol, isNew, err := symbols.symbol(entry.Value)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				if isNew {
+					rebusHeader = append(rebusHeader, fmt.Sprintf("%c=%s", symbol, puzzle.textToUTF8(entry.Value)))
+				}
+
+				ch = symbol
+
+				if circled {
+					warnings.add("circles", "circled rebus cells can not be marked in xd and were not circled")
+				}
+			case ch == EmptySolutionSquare:
+				ch = xdEmpty
+
+				if circled {
+					warnings.add("circles", "circled cells without an answer can not be marked in xd and were not circled")
+				}
+			case circled && ch >= 'A' && ch <= 'Z':
+				ch += 'a' - 'A'
+				hasCircles = true
+			}
+
+			grid.WriteByte(ch)
+		}
+
+		grid.WriteString("\n")
+	}
+
+	var out strings.Builder
+
+	headers := [][2]string{
+		{"Title", puzzle.Title},
+		{"Author", puzzle.Author},
+		{"Copyright", puzzle.Copyright},
+	}
+
+	for _, header := range headers {
+		if header[1] != "" {
+			fmt.Fprintf(&out, "%s: %s\n", header[0], strings.ReplaceAll(puzzle.textToUTF8(header[1]), "\n", " "))
+		}
+	}
+
+	if len(rebusHeader) > 0 {
+		fmt.Fprintf(&out, "Rebus: %s\n", strings.Join(rebusHeader, " "))
+	}
+
+	if hasCircles {
+		out.WriteString("Special: circle\n")
+	}
+
+	out.WriteString("\n\n")
+	out.WriteString(grid.String())
+	out.WriteString("\n\n")
+
+	for _, dir := range []Direction{Across, Down} {
+		clues := puzzle.GetCluesByDirection(dir)
+
+		for _, clue := range clues {
+			word := Word{"", clue.Num, clue.StartX, clue.StartY, dir}
+			text := strings.ReplaceAll(puzzle.textToUTF8(clue.Clue), "\n", " ")
+
+			fmt.Fprintf(&out, "%c%d. %s ~ %s\n", directionName(dir)[0], clue.Num, text, puzzle.textToUTF8(puzzle.WordAnswer(word)))
+		}
+
+		if dir == Across && len(clues) > 0 {
+			out.WriteString("\n")
+		}
+	}
+
+	if puzzle.Notes != "" {
+		out.WriteString("\n\n")
+		out.WriteString(puzzle.textToUTF8(puzzle.Notes))
+		out.WriteString("\n")
+	}
+
+	return []byte(out.String()), warnings, nil
+}
+
+// parseXdRebus parses a Rebus header in the form "1=HEART 2=CLUB".
+func parseXdRebus(header string) (map[byte]string, error) {
+	rebuses := map[byte]string{}
+
+	for part := range strings.FieldsSeq(header) {
+		symbol, value, ok := strings.Cut(part, "=")
+		if !ok || len(symbol) != 1 || value == "" || symbol[0] == xdBlock {
+			return nil, InvalidXdRebusError
+		}
+
+		rebuses[symbol[0]] = strings.ToUpper(value)
+	}
+
+	return rebuses, nil
+}
@@ -0,0 +1,206 @@
+package puz_test
+
+import (
+	"errors"
+	puz "github.com/cqb13/puz-parser"
+	"slices"
+	"testing"
+)
+
+const sampleXd = `Title: Sample
+Author: Tester
+Copyright: © 2026
+Editor: Someone
+Rebus: 1=HEART
+Special: circle
+
+
+cAT
+A1E
+BE#
+
+
+A1. Pet ~ CAT
+A4. Love rebus ~ AHEARTE
+A5. Sleep here ~ BE
+
+D1. Taxi ~ CAB
+D2. Ape, maybe ~ AHEARTE
+D3. Tee ~ TE
+
+
+Some notes
+on two lines
+`
+
+func TestDecodeXd(t *testing.T) {
+	puzzle, warnings, err := puz.DecodeXd([]byte(sampleXd))
+	if err != nil {
+		t.Fatalf("Failed to decode xd: %v", err)
+	}
+
+	if puzzle.Title != "Sample" || puzzle.Author != "Tester" || puzzle.Copyright != "\xa9 2026" {
+		t.Fatalf("Failed to load headers, found %q, %q, %q", puzzle.Title, puzzle.Author, puzzle.Copyright)
+	}
+
+	if puzzle.Notes != "Some notes\non two lines" {
+		t.Fatalf("Failed to load notes, found %q", puzzle.Notes)
+	}
+
+	if puzzle.Board[0][0].Answer != 'C' || puzzle.Board[0][0].Markup != puz.SquareCircled {
+		t.Fatalf("Failed to convert lowercase letter to a circled cell")
+	}
+
+	if puzzle.Board[1][1].RebusKey == 0 || puzzle.Extras.RebusTable[0].Value != "HEART" {
+		t.Fatalf("Failed to convert rebus symbol")
+	}
+
+	clue, ok := puzzle.GetClueByNum(2, puz.Down)
+	if !ok || clue.Clue != "Ape, maybe" {
+		t.Fatalf("Failed to match clue to the grid")
+	}
+
+	if !slices.ContainsFunc(warnings, func(w puz.ConversionWarning) bool { return w.Feature == "headers" }) {
+		t.Errorf("Expected a warning for the Editor header, found %v", warnings)
+	}
+
+	// A5 is only 2 letters in the grid but the answer is correct, so no answer warnings should exist
+	if slices.ContainsFunc(warnings, func(w puz.ConversionWarning) bool { return w.Feature == "answers" }) {
+		t.Errorf("Found unexpected answer warning, %v", warnings)
+	}
+
+	if _, err := puz.EncodePuz(puzzle); err != nil {
+		t.Fatalf("Failed to encode converted puzzle: %v", err)
+	}
+}
+
+func TestDecodeXdErrors(t *testing.T) {
+	_, _, err := puz.DecodeXd([]byte("Title: T\nRebus: 1\n\n\nAB\nCD\n"))
+
+	var parseErr *puz.TextParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 2 || !errors.Is(err, puz.InvalidXdRebusError) {
+		t.Fatalf("Expected InvalidXdRebusError on line 2, found %v", err)
+	}
+
+	_, _, err = puz.DecodeXd([]byte("Title: T\n\n\nAB\nC\n"))
+	if !errors.As(err, &parseErr) || parseErr.Line != 5 || !errors.Is(err, puz.TextGridSizeMismatchError) {
+		t.Fatalf("Expected TextGridSizeMismatchError on line 5, found %v", err)
+	}
+}
+
+func TestXdEmptyClue(t *testing.T) {
+	original, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	clue, _ := original.GetClueByNum(1, puz.Across)
+	clue.Clue = ""
+
+	data, _, err := puz.EncodeXd(original)
+	if err != nil {
+		t.Fatalf("Failed to encode as xd: %v", err)
+	}
+
+	converted, _, err := puz.DecodeXd(data)
+	if err != nil {
+		t.Fatalf("Failed to decode xd: %v", err)
+	}
+
+	clue, ok := converted.GetClueByNum(1, puz.Across)
+	if !ok || clue.Clue != "" {
+		t.Fatalf("Expected 1 across to have an empty clue, found %q", clue.Clue)
+	}
+}
+
+func TestEncodeXdCircledEmptyCell(t *testing.T) {
+	puzzle, err := puz.DecodePuz(loadFile(t, "Crossword.puz"))
+	if err != nil {
+		t.Fatalf("Failed to decode Crossword.puz: %v", err)
+	}
+
+	puzzle.Board[0][0].Answer = puz.EmptySolutionSquare
+	puzzle.Board[0][0].SetCircled(true)
+
+	_, warnings, err := puz.EncodeXd(puzzle)
+	if err != nil {
+		t.Fatalf("Failed to encode as xd: %v", err)
+	}
+
+	if !slices.ContainsFunc(warnings, func(w puz.ConversionWarning) bool { return w.Feature == "circles" }) {
+		t.Fatalf("Expected a circles warning, found %v", warnings)
+	}
+}
+
+func TestXdRoundTrip(t *testing.T) {
+	testCases := []string{
+		"Crossword.puz",
+		"Crossword-EXT-Rebus.puz",
+		"NYT-Nov2193.puz",
+		"washpost.puz",
+	}
+
+	for _, name := range testCases {
+		t.Run(name, func(t *testing.T) {
+			original, err := puz.DecodePuz(loadFile(t, name))
+			if err != nil {
+				t.Fatalf("Failed to decode %s: %v", name, err)
+			}
+
+			data, _, err := puz.EncodeXd(original)
+			if err != nil {
+				t.Fatalf("Failed to encode %s as xd: %v", name, err)
+			}
+
+			converted, warnings, err := puz.DecodeXd(data)
+			if err != nil {
+				t.Fatalf("Failed to decode xd for %s: %v", name, err)
+			}
+
+			if len(warnings) != 0 {
+				t.Errorf("Found unexpected warnings decoding xd for %s: %v", name, warnings)
+			}
+
+			if converted.Title != original.Title || converted.Author != original.Author || converted.Copyright != original.Copyright || converted.Notes != original.Notes {
+				t.Errorf("Strings changed during xd round trip")
+			}
+
+			for y := range original.Board {
+				for x := range original.Board[y] {
+					a := original.Board[y][x]
+					b := converted.Board[y][x]
+
+					if a.Answer != b.Answer || (a.RebusKey == 0) != (b.RebusKey == 0) || a.Markup != b.Markup {
+						t.Fatalf("Cell x: %d y: %d changed during xd round trip", x, y)
+					}
+				}
+			}
+
+			if !slices.Equal(original.Clues(), converted.Clues()) {
+				t.Errorf("Clues changed during xd round trip")
+			}
+
+			for y := range original.Board {
+				for x := range original.Board[y] {
+					if rebusValue(original, x, y) != rebusValue(converted, x, y) {
+						t.Fatalf("Rebus at x: %d y: %d changed during xd round trip", x, y)
+					}
+				}
+			}
+
+			if _, err := puz.EncodePuz(converted); err != nil {
+				t.Fatalf("Failed to encode converted %s: %v", name, err)
+			}
+		})
+	}
+}
+
+func rebusValue(p *puz.Puzzle, x int, y int) string {
+	for _, entry := range p.Extras.RebusTable {
+		if entry.Key == int(p.Board[y][x].RebusKey) {
+			return entry.Value
+		}
+	}
+
+	return ""
+}